@@ -0,0 +1,149 @@
+// Package alpine provides XSS-safe gomponents attributes for Alpine.js.
+//
+// Go values are encoded as JSON, which is a valid JavaScript literal. The
+// encoder escapes <, >, & and the line separators U+2028/U+2029, and
+// gomponents escapes quotes when rendering the attribute - so server state
+// can be passed into components without hand-escaping.
+//
+// Never build expressions by concatenating user input; use JS, Call or
+// Component to embed values.
+//
+// # Usage Example
+//
+//	h.Div(
+//	    alpine.Data(map[string]any{"open": false, "user": user}),  // x-data='{"open":false,"user":{...}}'
+//	    h.Button(alpine.On("click", "open = !open"), g.Text("Toggle")),
+//	    h.Div(alpine.Show("open"), alpine.Text("user.name")),
+//	)
+//
+//	// Registered component (Alpine.data("dropdown", ...)) with server arguments
+//	h.Div(alpine.Component("dropdown", items, selectedID))  // x-data='dropdown([...],42)'
+//
+//	// Expressions with embedded values
+//	alpine.Init(alpine.Call("$dispatch", "loaded", payload))  // x-init='$dispatch("loaded",{...})'
+//
+// # Dependencies
+//
+// Requires: stdlib (encoding/json, regexp), gomponents
+package alpine
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	g "maragu.dev/gomponents"
+)
+
+// identifierRe matches (dotted) JavaScript identifiers like "dropdown",
+// "$dispatch" or "forms.validate".
+var identifierRe = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)*$`)
+
+// JS returns v encoded as a JavaScript literal (JSON) for use in expressions.
+//
+// Panics if v cannot be encoded as JSON (funcs, channels, NaN or infinite
+// floats).
+//
+// Example:
+//
+//	alpine.On("click", "select("+alpine.JS(item.ID)+")")
+func JS(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("alpine: encode %T: %v", v, err))
+	}
+	return string(b)
+}
+
+// Call returns a call expression fn(args...) with JSON-encoded arguments.
+//
+// fn must be a (dotted) JavaScript identifier; Call panics otherwise, since
+// the function name is code and must never come from user input.
+func Call(fn string, args ...any) string {
+	if !identifierRe.MatchString(fn) {
+		panic(fmt.Sprintf("alpine: invalid function name %q", fn))
+	}
+	encoded := make([]string, len(args))
+	for i, a := range args {
+		encoded[i] = JS(a)
+	}
+	return fn + "(" + strings.Join(encoded, ",") + ")"
+}
+
+// Data returns x-data with v encoded as a JSON object literal.
+// v should be a struct or map; use DataExpr for component objects with methods.
+func Data(v any) g.Node { return g.Attr("x-data", JS(v)) }
+
+// DataExpr returns x-data with a raw expression (trusted code only).
+func DataExpr(expr string) g.Node { return g.Attr("x-data", expr) }
+
+// Component returns x-data calling a registered component (Alpine.data)
+// with JSON-encoded arguments. See Call.
+func Component(name string, args ...any) g.Node { return g.Attr("x-data", Call(name, args...)) }
+
+// Init returns x-init with an expression.
+func Init(expr string) g.Node { return g.Attr("x-init", expr) }
+
+// Bind returns x-bind:<attr> with an expression.
+func Bind(attr, expr string) g.Node { return g.Attr("x-bind:"+attr, expr) }
+
+// BindValue returns x-bind:<attr> with v encoded as a literal.
+//
+// Example:
+//
+//	alpine.BindValue("class", map[string]bool{"active": true})  // x-bind:class='{"active":true}'
+func BindValue(attr string, v any) g.Node { return g.Attr("x-bind:"+attr, JS(v)) }
+
+// On returns x-on:<event> with an expression.
+// Modifiers are appended with dots, e.g. On("submit.prevent", "save()").
+func On(event, expr string) g.Node { return g.Attr("x-on:"+event, expr) }
+
+// Model returns x-model. Use ModelWith for modifiers.
+func Model(expr string) g.Node { return g.Attr("x-model", expr) }
+
+// ModelWith returns x-model.<modifiers> (e.g. ModelWith("q", "debounce", "500ms")).
+func ModelWith(expr string, modifiers ...string) g.Node {
+	return g.Attr("x-model."+strings.Join(modifiers, "."), expr)
+}
+
+// Show returns x-show.
+func Show(expr string) g.Node { return g.Attr("x-show", expr) }
+
+// Text returns x-text (content is set as text, never as HTML).
+func Text(expr string) g.Node { return g.Attr("x-text", expr) }
+
+// If returns x-if (use on <template>).
+func If(expr string) g.Node { return g.Attr("x-if", expr) }
+
+// For returns x-for (use on <template>), e.g. For("item in items").
+func For(expr string) g.Node { return g.Attr("x-for", expr) }
+
+// Key returns :key for x-for loops.
+func Key(expr string) g.Node { return g.Attr(":key", expr) }
+
+// Effect returns x-effect.
+func Effect(expr string) g.Node { return g.Attr("x-effect", expr) }
+
+// Ref returns x-ref.
+func Ref(name string) g.Node { return g.Attr("x-ref", name) }
+
+// ID returns x-id with JSON-encoded names.
+func ID(names ...string) g.Node { return g.Attr("x-id", JS(names)) }
+
+// Teleport returns x-teleport (use on <template>).
+func Teleport(selector string) g.Node { return g.Attr("x-teleport", selector) }
+
+// Transition returns x-transition (optionally with modifiers, e.g. "opacity", "duration.300ms").
+func Transition(modifiers ...string) g.Node {
+	if len(modifiers) == 0 {
+		return g.Attr("x-transition")
+	}
+	return g.Attr("x-transition." + strings.Join(modifiers, "."))
+}
+
+// Cloak returns x-cloak (hide until Alpine is initialized).
+func Cloak() g.Node { return g.Attr("x-cloak") }
+
+// Ignore returns x-ignore.
+func Ignore() g.Node { return g.Attr("x-ignore") }
@@ -0,0 +1,86 @@
+package alpine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// render renders a node to a string.
+func render(t *testing.T, n g.Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := n.Render(&buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	return buf.String()
+}
+
+// TestAttributes tests attribute rendering
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{"data", Data(map[string]any{"open": false, "n": 3}), ` x-data="{&#34;n&#34;:3,&#34;open&#34;:false}"`},
+		{"component", Component("dropdown", []int{1, 2}, "x"), ` x-data="dropdown([1,2],&#34;x&#34;)"`},
+		{"init call", Init(Call("$dispatch", "loaded")), ` x-init="$dispatch(&#34;loaded&#34;)"`},
+		{"bind value", BindValue("class", map[string]bool{"active": true}), ` x-bind:class="{&#34;active&#34;:true}"`},
+		{"on", On("submit.prevent", "save()"), ` x-on:submit.prevent="save()"`},
+		{"model with", ModelWith("q", "debounce", "500ms"), ` x-model.debounce.500ms="q"`},
+		{"transition", Transition(), ` x-transition`},
+		{"transition modifiers", Transition("opacity", "duration.300ms"), ` x-transition.opacity.duration.300ms`},
+		{"id", ID("tab", "panel"), ` x-id="[&#34;tab&#34;,&#34;panel&#34;]"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, tt.node); got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestData_Injection tests that hostile values cannot break out of the attribute or script context
+func TestData_Injection(t *testing.T) {
+	payloads := []string{
+		`"></div><script>alert(1)</script>`,
+		`'; alert(1); '`,
+		"</script>\u2028alert(1)",
+		"line\u2028separator\u2029paragraph",
+		"` + alert(1) + `",
+	}
+
+	for _, p := range payloads {
+		got := render(t, h.Div(Data(map[string]string{"name": p}), On("click", Call("greet", p))))
+
+		for _, bad := range []string{"<script", "</script", "\u2028", "\u2029", `'`} {
+			if strings.Contains(got, bad) {
+				t.Errorf("payload %q: output contains %q: %s", p, bad, got)
+			}
+		}
+		// The only double quotes are the attribute delimiters
+		if n := strings.Count(got, `"`); n != 4 {
+			t.Errorf("payload %q: expected 4 attribute quotes, got %d: %s", p, n, got)
+		}
+	}
+}
+
+// TestCall_InvalidName tests that function names are validated
+func TestCall_InvalidName(t *testing.T) {
+	for _, name := range []string{"", "alert(1);f", "a b", "1abc", "a..b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for function name %q", name)
+				}
+			}()
+			Call(name)
+		}()
+	}
+}
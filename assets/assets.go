@@ -0,0 +1,188 @@
+// Package assets ships the frontend runtime that belongs to hagg-lib.
+//
+// The JavaScript files in this package are the counterpart of the Go side of
+// the event system and must stay in sync with it:
+//
+//   - events.js: Dispatches initial-events (hxevents.RenderInitialEvents)
+//   - toast.js:  Renders "toast" events (toast.Toast.Notify)
+//
+// Because the files are embedded into the module, every app gets exactly the
+// runtime that matches the library version it was built with.
+//
+// # Serving
+//
+// Handler serves the embedded files. Files are addressed by a content
+// fingerprint (e.g. "events.3f2a1b9c0d.js") and cached as immutable:
+//
+//	r.Handle(assets.Prefix+"/*", assets.Handler())
+//
+// # Script Tags
+//
+// Scripts renders the <script> tags for all runtime files (basePath-aware):
+//
+//	// In Head():
+//	view.Script(ctx, "/static/js/surreal_v1.3.4.js"),
+//	assets.Scripts(ctx.Req),
+//
+// # Dependencies
+//
+// Requires: stdlib (embed, net/http, crypto/sha256), gomponents, view package
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// Prefix is the URL prefix (relative to the base path) under which
+// Handler is expected to be mounted. Scripts generates URLs below it.
+const Prefix = "/static/hagg"
+
+// Runtime files in load order.
+const (
+	EventsJS = "events.js"
+	ToastJS  = "toast.js"
+)
+
+//go:embed js/*.js
+var files embed.FS
+
+// asset is a single embedded file with its precomputed fingerprint.
+type asset struct {
+	name          string // Plain name (e.g. "events.js")
+	fingerprinted string // Name with content hash (e.g. "events.3f2a1b9c0d.js")
+	etag          string // Strong ETag derived from the content hash
+	data          []byte
+}
+
+var (
+	byName = make(map[string]*asset) // Lookup by plain and fingerprinted name
+	order  = []string{EventsJS, ToastJS}
+)
+
+func init() {
+	entries, err := fs.ReadDir(files, "js")
+	if err != nil {
+		panic("assets: read embedded files: " + err.Error())
+	}
+
+	for _, entry := range entries {
+		data, err := fs.ReadFile(files, "js/"+entry.Name())
+		if err != nil {
+			panic("assets: read embedded file: " + err.Error())
+		}
+
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])[:10]
+		ext := path.Ext(entry.Name())
+
+		a := &asset{
+			name:          entry.Name(),
+			fingerprinted: strings.TrimSuffix(entry.Name(), ext) + "." + hash + ext,
+			etag:          `"` + hash + `"`,
+			data:          data,
+		}
+		byName[a.name] = a
+		byName[a.fingerprinted] = a
+	}
+}
+
+// Path returns the fingerprinted file name for a runtime file.
+// Returns an empty string if the file is unknown.
+//
+// Example:
+//
+//	assets.Path(assets.EventsJS)  // "events.3f2a1b9c0d.js"
+func Path(name string) string {
+	a, ok := byName[name]
+	if !ok {
+		return ""
+	}
+	return a.fingerprinted
+}
+
+// URL returns the basePath-aware, fingerprinted URL of a runtime file.
+//
+// Example:
+//
+//	assets.URL(req, assets.ToastJS)  // "/app/static/hagg/toast.3f2a1b9c0d.js"
+func URL(req *http.Request, name string) string {
+	return view.URLString(req, Prefix+"/"+Path(name))
+}
+
+// Handler returns an http.Handler that serves the embedded runtime files.
+//
+// Files are matched by the last path segment, so the handler works whether
+// or not the router strips the mount prefix.
+//
+// Caching:
+//   - Fingerprinted names: Cache-Control: public, max-age=31536000, immutable
+//   - Plain names (e.g. "events.js"): Cache-Control: no-cache (revalidated via ETag)
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := path.Base(r.URL.Path)
+		a, ok := byName[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		if name == a.fingerprinted {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("ETag", a.etag)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(a.data))
+	})
+}
+
+// Scripts renders deferred <script> tags for all runtime files in load order.
+// If middleware.CSP is used, the tags carry the request's nonce.
+//
+// Because the scripts are deferred, inline scripts that call showToast
+// must wait for DOMContentLoaded; the hxevents toast renderers do.
+//
+// Example output:
+//
+//	<script src="/app/static/hagg/events.3f2a1b9c0d.js" defer></script>
+//	<script src="/app/static/hagg/toast.8e1f0a2b3c.js" defer></script>
+func Scripts(req *http.Request) g.Node {
+	nodes := make([]g.Node, 0, len(order))
+	for _, name := range order {
+		nodes = append(nodes, h.Script(h.Src(URL(req, name)), h.Defer(), nonceAttr(req)))
+	}
+	return g.Group(nodes)
+}
+
+// nonceAttr returns the CSP nonce attribute for script tags.
+// Returns nil if no nonce is set (middleware.CSP not used).
+func nonceAttr(req *http.Request) g.Node {
+	nonce, _ := req.Context().Value(ctxkeys.CSPNonce).(string)
+	if nonce == "" {
+		return nil
+	}
+	return g.Attr("nonce", nonce)
+}
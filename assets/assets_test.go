@@ -0,0 +1,92 @@
+package assets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// TestPath tests fingerprinted file names
+func TestPath(t *testing.T) {
+	for _, name := range []string{EventsJS, ToastJS} {
+		fp := Path(name)
+		if fp == "" {
+			t.Fatalf("Path(%q) returned empty string", name)
+		}
+		if fp == name {
+			t.Errorf("Path(%q) should contain a fingerprint, got %q", name, fp)
+		}
+		if !strings.HasSuffix(fp, ".js") {
+			t.Errorf("Path(%q) should keep the .js extension, got %q", name, fp)
+		}
+	}
+
+	if Path("unknown.js") != "" {
+		t.Error("Path() should return empty string for unknown files")
+	}
+}
+
+// TestHandler tests serving and caching headers
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		method       string
+		expectedCode int
+		expectedCC   string
+	}{
+		{"fingerprinted", Prefix + "/" + Path(EventsJS), http.MethodGet, http.StatusOK, "public, max-age=31536000, immutable"},
+		{"plain name", Prefix + "/" + ToastJS, http.MethodGet, http.StatusOK, "no-cache"},
+		{"head", "/" + Path(ToastJS), http.MethodHead, http.StatusOK, "public, max-age=31536000, immutable"},
+		{"unknown file", Prefix + "/missing.js", http.MethodGet, http.StatusNotFound, ""},
+		{"wrong method", Prefix + "/" + Path(EventsJS), http.MethodPost, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+
+			Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCC != "" && rec.Header().Get("Cache-Control") != tt.expectedCC {
+				t.Errorf("expected Cache-Control %q, got %q", tt.expectedCC, rec.Header().Get("Cache-Control"))
+			}
+			if tt.expectedCode == http.StatusOK && !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/javascript") {
+				t.Errorf("expected JavaScript content type, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// TestScripts tests script tag rendering with base path
+func TestScripts(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxkeys.BasePath, "/app"))
+
+	var sb strings.Builder
+	if err := Scripts(req).Render(&sb); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	html := sb.String()
+
+	eventsTag := `<script src="/app/static/hagg/` + Path(EventsJS) + `" defer>`
+	toastTag := `<script src="/app/static/hagg/` + Path(ToastJS) + `" defer>`
+
+	if !strings.Contains(html, eventsTag) {
+		t.Errorf("expected %q in output, got %q", eventsTag, html)
+	}
+	if !strings.Contains(html, toastTag) {
+		t.Errorf("expected %q in output, got %q", toastTag, html)
+	}
+	if strings.Index(html, eventsTag) > strings.Index(html, toastTag) {
+		t.Error("events.js should be loaded before toast.js")
+	}
+}
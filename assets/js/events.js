@@ -0,0 +1,56 @@
+/*
+ * hagg-lib events.js
+ *
+ * Frontend counterpart of the hxevents package.
+ *
+ *   - HTMX requests: hxevents.Commit() writes HX-Trigger headers. HTMX itself
+ *     dispatches those events on the requesting element (they bubble to the
+ *     document), so nothing needs to be done here.
+ *   - Full-page loads: hxevents.RenderInitialEvents() renders
+ *     <script type="application/json" id="initial-events">[...]</script>.
+ *     This runtime reads that script on DOMContentLoaded and dispatches every
+ *     entry as a CustomEvent on document.body, so listeners work the same way
+ *     for both delivery paths.
+ *
+ * Event shape: {"name": "toast", "payload": {...}}
+ */
+(function () {
+  "use strict";
+
+  function dispatch(name, payload) {
+    var target = document.body || document;
+    target.dispatchEvent(
+      new CustomEvent(name, { detail: payload, bubbles: true })
+    );
+  }
+
+  function processInitialEvents() {
+    var el = document.getElementById("initial-events");
+    if (!el) {
+      return;
+    }
+
+    var events;
+    try {
+      events = JSON.parse(el.textContent || "[]");
+    } catch (err) {
+      console.error("hagg: invalid initial-events payload", err);
+      return;
+    }
+
+    // Remove the script so a later body swap cannot replay the events.
+    el.remove();
+
+    for (var i = 0; i < events.length; i++) {
+      dispatch(events[i].name, events[i].payload);
+    }
+  }
+
+  window.haggEvents = { dispatch: dispatch };
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", processInitialEvents);
+  } else {
+    processInitialEvents();
+  }
+})();
@@ -0,0 +1,113 @@
+/*
+ * hagg-lib toast.js
+ *
+ * Renders toast notifications emitted by the toast package.
+ *
+ * Listens for "toast" events (from HX-Trigger headers or initial-events) and
+ * exposes window.showToast(payload) for direct calls.
+ *
+ * Payload (see toast.Toast.Notify):
+ *   {"message": "...", "level": "success|error|warning|info",
+ *    "timeout": 3000, "position": "bottom-right|top-right|bottom-left|top-left"}
+ *
+ * Markup uses the .toast / .toast-<level> classes from INTEGRATION.md.
+ * Icons match toast/icons.go.
+ */
+(function () {
+  "use strict";
+
+  var ICONS = {
+    success:
+      '<svg width="20" height="20" viewBox="0 0 20 20" fill="none" xmlns="http://www.w3.org/2000/svg">' +
+      '<circle cx="10" cy="10" r="9" stroke="#43a047" stroke-width="2"/>' +
+      '<path d="M6 10l2.5 2.5L14 7" stroke="#43a047" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>' +
+      "</svg>",
+    error:
+      '<svg width="20" height="20" viewBox="0 0 20 20" fill="none" xmlns="http://www.w3.org/2000/svg">' +
+      '<circle cx="10" cy="10" r="9" stroke="#e53935" stroke-width="2"/>' +
+      '<path d="M7 7l6 6M13 7l-6 6" stroke="#e53935" stroke-width="2" stroke-linecap="round"/>' +
+      "</svg>",
+    warning:
+      '<svg width="20" height="20" viewBox="0 0 20 20" fill="none" xmlns="http://www.w3.org/2000/svg">' +
+      '<path d="M10 2L2 17h16L10 2z" stroke="#fb8c00" stroke-width="2" stroke-linejoin="round"/>' +
+      '<path d="M10 8v3M10 14h.01" stroke="#fb8c00" stroke-width="2" stroke-linecap="round"/>' +
+      "</svg>",
+    info:
+      '<svg width="20" height="20" viewBox="0 0 20 20" fill="none" xmlns="http://www.w3.org/2000/svg">' +
+      '<circle cx="10" cy="10" r="9" stroke="#1095c1" stroke-width="2"/>' +
+      '<path d="M10 9v5M10 6h.01" stroke="#1095c1" stroke-width="2" stroke-linecap="round"/>' +
+      "</svg>",
+  };
+
+  var POSITIONS = ["bottom-right", "top-right", "bottom-left", "top-left"];
+
+  function container(position) {
+    var id = "toast-container-" + position;
+    var el = document.getElementById(id);
+    if (!el) {
+      el = document.createElement("div");
+      el.id = id;
+      el.className = "toast-container toast-container-" + position;
+      el.setAttribute("aria-live", "polite");
+      document.body.appendChild(el);
+    }
+    return el;
+  }
+
+  function dismiss(el) {
+    el.style.opacity = "0";
+    setTimeout(function () {
+      el.remove();
+    }, 300);
+  }
+
+  function showToast(opts) {
+    opts = opts || {};
+
+    var level = ICONS[opts.level] ? opts.level : "info";
+    var position =
+      POSITIONS.indexOf(opts.position) >= 0 ? opts.position : "bottom-right";
+    var timeout = typeof opts.timeout === "number" ? opts.timeout : 3000;
+
+    var el = document.createElement("div");
+    el.className = "toast toast-" + level;
+    el.setAttribute("role", level === "error" ? "alert" : "status");
+
+    var icon = document.createElement("span");
+    icon.className = "toast-icon";
+    icon.innerHTML = ICONS[level];
+
+    var message = document.createElement("span");
+    message.className = "toast-message";
+    message.textContent = opts.message || "";
+
+    var close = document.createElement("button");
+    close.type = "button";
+    close.className = "toast-close";
+    close.setAttribute("aria-label", "Close");
+    close.textContent = "×";
+    close.addEventListener("click", function () {
+      dismiss(el);
+    });
+
+    el.appendChild(icon);
+    el.appendChild(message);
+    el.appendChild(close);
+    container(position).appendChild(el);
+
+    // timeout 0 = stay until closed (toast.Stay())
+    if (timeout > 0) {
+      setTimeout(function () {
+        dismiss(el);
+      }, timeout);
+    }
+
+    return el;
+  }
+
+  window.showToast = showToast;
+
+  document.addEventListener("toast", function (evt) {
+    showToast(evt.detail);
+  });
+})();
@@ -0,0 +1,282 @@
+// Package admin provides a mountable role and permission admin UI for casbinx.
+//
+// The UI lists role assignments and policies, assigns and revokes roles,
+// adds and removes policies and answers "can X do Y" with the deciding
+// rule (casbinx.Perm.Explain). Changes go through casbinx.Perm, so they
+// are validated and saved to the policy file; htmx requests get feedback
+// as toasts. The role select offers casbinx.Perm.KnownRoles: the declared
+// schema roles, or every role that has permissions or members.
+//
+// # Usage Example
+//
+//	r.Mount("/admin/permissions", admin.New(admin.Config{
+//	    Perm:    perm,
+//	    Wrapper: wrapper,
+//	    Subject: userFromSession,
+//	    Prefix:  "/admin/permissions",
+//	    Layout:  func(ctx *handler.Context, title string, content g.Node) g.Node {
+//	        return layout.Page(ctx, title, content)
+//	    },
+//	}))
+//
+// Access requires the permission Config.Permission (default "casbin:admin")
+// for r = sub, act models. Use Config.Protect for other models.
+//
+// Forms use htmx (hx-post) and fall back to plain form posts, which
+// redirect (303 See Other) back to the admin page after a change. Include
+// the csrf middleware; its token is added to every form.
+//
+// # Dependencies
+//
+// Requires: casbinx, handler, hx, csrf, hxevents, view packages, gomponents
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	g "maragu.dev/gomponents"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+	"github.com/axelrhd/hagg-lib/handler"
+	"github.com/axelrhd/hagg-lib/hxevents"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// LayoutFunc wraps the admin content into the app's page layout for
+// full-page loads. Include htmx and assets.Scripts in the layout so forms
+// update in place and show toasts.
+type LayoutFunc func(ctx *handler.Context, title string, content g.Node) g.Node
+
+// Config configures the admin UI.
+type Config struct {
+	// Perm to manage (required).
+	Perm *casbinx.Perm
+
+	// Wrapper for handlers and errors. Default: handler.NewWrapper(slog.Default()).
+	Wrapper *handler.Wrapper
+
+	// Subject resolves the current user (required unless Protect is set).
+	Subject casbinx.SubjectFunc
+
+	// Permission required to use the UI. Default: "casbin:admin".
+	Permission string
+
+	// Protect replaces the default access check (casbinx.Middleware with
+	// Subject and Permission). Required for resource or domain models,
+	// e.g. casbinx.MiddlewareIn.
+	Protect func(http.Handler) http.Handler
+
+	// Prefix is the app-relative path the handler is mounted at, e.g.
+	// "/admin/permissions". Works with and without http.StripPrefix.
+	Prefix string
+
+	// Layout for full-page loads. Default: DefaultLayout.
+	Layout LayoutFunc
+
+	// Title of the page. Default: "Permissions".
+	Title string
+}
+
+// admin serves the admin UI.
+type admin struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// New creates the admin UI handler.
+//
+// Panics if Perm is missing, or if neither Subject nor Protect is set -
+// an unprotected admin UI must not be mountable by accident.
+func New(cfg Config) http.Handler {
+	if cfg.Perm == nil {
+		panic("admin: Config.Perm is required")
+	}
+	if cfg.Subject == nil && cfg.Protect == nil {
+		panic("admin: Config.Subject or Config.Protect is required")
+	}
+	if cfg.Wrapper == nil {
+		cfg.Wrapper = handler.NewWrapper(slog.Default())
+	}
+	if cfg.Permission == "" {
+		cfg.Permission = "casbin:admin"
+	}
+	if cfg.Protect == nil {
+		cfg.Protect = casbinx.MiddlewareWithConfig(cfg.Perm, casbinx.MiddlewareConfig{
+			Subject:      cfg.Subject,
+			Action:       casbinx.Action(cfg.Permission),
+			ErrorHandler: cfg.Wrapper.Error,
+		})
+	}
+	if cfg.Layout == nil {
+		cfg.Layout = DefaultLayout
+	}
+	if cfg.Title == "" {
+		cfg.Title = "Permissions"
+	}
+	cfg.Prefix = strings.TrimSuffix(cfg.Prefix, "/")
+
+	a := &admin{cfg: cfg, mux: http.NewServeMux()}
+	w := cfg.Wrapper
+	a.mux.HandleFunc("GET /{$}", w.Wrap(a.index))
+	a.mux.HandleFunc("POST /roles/assign", w.Wrap(a.assignRole))
+	a.mux.HandleFunc("POST /roles/revoke", w.Wrap(a.revokeRole))
+	a.mux.HandleFunc("POST /policies/add", w.Wrap(a.addPolicy))
+	a.mux.HandleFunc("POST /policies/remove", w.Wrap(a.removePolicy))
+	a.mux.HandleFunc("POST /check", w.Wrap(a.check))
+
+	return cfg.Protect(http.HandlerFunc(a.serveHTTP))
+}
+
+// serveHTTP routes the request relative to the prefix.
+func (a *admin) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	if a.cfg.Prefix != "" && (p == a.cfg.Prefix || strings.HasPrefix(p, a.cfg.Prefix+"/")) {
+		p = strings.TrimPrefix(p, a.cfg.Prefix)
+	}
+	if p == "" {
+		p = "/"
+	}
+
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = p
+	r2.URL.RawPath = ""
+	a.mux.ServeHTTP(w, r2)
+}
+
+// index renders the admin page.
+func (a *admin) index(ctx *handler.Context) error {
+	return a.render(ctx, nil)
+}
+
+// assignRole assigns a role to a user.
+func (a *admin) assignRole(ctx *handler.Context) error {
+	user, role, domain := formValue(ctx, "user"), formValue(ctx, "role"), formDomain(ctx)
+	if err := a.cfg.Perm.AssignRole(user, role, domain...); err != nil {
+		return changeError(err)
+	}
+	a.audit(ctx, "role assigned", "user", user, "role", role, "domain", domain)
+	ctx.Toast(fmt.Sprintf("Assigned role %s to %s.", role, user)).Success().Notify()
+	return a.changed(ctx)
+}
+
+// revokeRole removes a role from a user.
+func (a *admin) revokeRole(ctx *handler.Context) error {
+	user, role, domain := formValue(ctx, "user"), formValue(ctx, "role"), formDomain(ctx)
+	if err := a.cfg.Perm.RevokeRole(user, role, domain...); err != nil {
+		return changeError(err)
+	}
+	a.audit(ctx, "role revoked", "user", user, "role", role, "domain", domain)
+	ctx.Toast(fmt.Sprintf("Revoked role %s from %s.", role, user)).Success().Notify()
+	return a.changed(ctx)
+}
+
+// addPolicy adds a policy rule.
+func (a *admin) addPolicy(ctx *handler.Context) error {
+	rule := formValues(ctx, "v", len(policyFields(a.cfg.Perm)))
+	if err := a.cfg.Perm.AddPolicy(rule...); err != nil {
+		return changeError(err)
+	}
+	a.audit(ctx, "policy added", "rule", rule)
+	ctx.Toast("Added policy " + strings.Join(rule, ", ") + ".").Success().Notify()
+	return a.changed(ctx)
+}
+
+// removePolicy removes a policy rule.
+func (a *admin) removePolicy(ctx *handler.Context) error {
+	rule := formValues(ctx, "v", len(policyFields(a.cfg.Perm)))
+	if err := a.cfg.Perm.RemovePolicy(rule...); err != nil {
+		return changeError(err)
+	}
+	a.audit(ctx, "policy removed", "rule", rule)
+	ctx.Toast("Removed policy " + strings.Join(rule, ", ") + ".").Success().Notify()
+	return a.changed(ctx)
+}
+
+// check explains a request ("can X do Y").
+func (a *admin) check(ctx *handler.Context) error {
+	rvals := formValues(ctx, "r", len(a.cfg.Perm.RequestFields()))
+	exp, err := a.cfg.Perm.Explain(rvals...)
+	if err != nil {
+		return changeError(err)
+	}
+	if hxevents.IsHtmxRequest(ctx.Req.Header) {
+		return ctx.Render(checkResult(exp))
+	}
+	return a.render(ctx, exp)
+}
+
+// changed responds to a successful change: the updated fragment (with the
+// toast) for htmx requests, a redirect to the admin page otherwise, so
+// reloading the page does not repeat the change.
+func (a *admin) changed(ctx *handler.Context) error {
+	if hxevents.IsHtmxRequest(ctx.Req.Header) {
+		return a.render(ctx, nil)
+	}
+	http.Redirect(ctx.Res, ctx.Req, view.URLString(ctx.Req, a.cfg.Prefix+"/"), http.StatusSeeOther)
+	return nil
+}
+
+// render renders the admin content: the fragment for htmx requests, the
+// full page otherwise.
+func (a *admin) render(ctx *handler.Context, exp *casbinx.Explanation) error {
+	content := a.content(ctx.Req, exp)
+	if hxevents.IsHtmxRequest(ctx.Req.Header) {
+		return ctx.Render(content)
+	}
+	return ctx.Render(a.cfg.Layout(ctx, a.cfg.Title, content))
+}
+
+// audit logs a policy change with the acting subject.
+func (a *admin) audit(ctx *handler.Context, msg string, args ...any) {
+	args = append(args, "by", casbinx.SubjectFromRequest(ctx.Req))
+	ctx.Logger().Info("casbin admin: "+msg, args...)
+}
+
+// changeError maps casbinx errors to user-facing handler errors.
+func changeError(err error) error {
+	switch {
+	case errors.Is(err, casbinx.ErrRuleExists):
+		return handler.WrapError(http.StatusConflict, "This rule already exists.", err)
+	case errors.Is(err, casbinx.ErrRuleNotFound):
+		return handler.WrapError(http.StatusNotFound, "This rule does not exist.", err)
+	case errors.Is(err, casbinx.ErrUnknownRole),
+		errors.Is(err, casbinx.ErrUnknownAction),
+		errors.Is(err, casbinx.ErrInvalidRule),
+		errors.Is(err, casbinx.ErrShapeMismatch):
+		return handler.WrapError(http.StatusUnprocessableEntity, userMessage(err), err)
+	default:
+		return err
+	}
+}
+
+// userMessage strips the package prefix from validation errors.
+func userMessage(err error) string {
+	msg := strings.TrimPrefix(err.Error(), "casbinx: ")
+	return strings.ToUpper(msg[:1]) + msg[1:] + "."
+}
+
+// formValue returns a trimmed form value.
+func formValue(ctx *handler.Context, key string) string {
+	return strings.TrimSpace(ctx.Req.PostFormValue(key))
+}
+
+// formDomain returns the optional domain as variadic argument.
+func formDomain(ctx *handler.Context) []string {
+	if d := formValue(ctx, "domain"); d != "" {
+		return []string{d}
+	}
+	return nil
+}
+
+// formValues returns the form values prefix0..prefix(n-1).
+func formValues(ctx *handler.Context, prefix string, n int) []string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = formValue(ctx, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return values
+}
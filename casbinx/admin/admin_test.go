@@ -0,0 +1,300 @@
+package admin
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+	"github.com/axelrhd/hagg-lib/handler"
+)
+
+const testModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.act == p.act
+`
+
+const testPolicy = `p, admin, casbin:admin
+p, reader, posts:read
+p, editor, posts:write
+g, root, admin
+g, alice, editor
+`
+
+// newAdmin creates the admin UI mounted at /admin with a file-backed Perm.
+func newAdmin(t *testing.T) (http.Handler, string) {
+	t.Helper()
+	return newAdminWithSchema(t, casbinx.Schema{
+		Roles:   []string{"admin", "editor", "reader"},
+		Actions: []string{"casbin:admin", "posts:read", "posts:write"},
+	})
+}
+
+// newAdminWithSchema is newAdmin with the given schema.
+func newAdminWithSchema(t *testing.T, schema casbinx.Schema) (http.Handler, string) {
+	t.Helper()
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.conf")
+	policyPath := filepath.Join(dir, "policy.csv")
+	if err := os.WriteFile(modelPath, []byte(testModel), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	perm, err := casbinx.NewFilePerm(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm.SetSchema(schema)
+
+	h := New(Config{
+		Perm:    perm,
+		Wrapper: handler.NewWrapper(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		Subject: casbinx.SubjectFromHeader("X-User"),
+		Prefix:  "/admin",
+	})
+	return h, policyPath
+}
+
+// do sends a request as user.
+func do(h http.Handler, method, path, user string, form url.Values, htmx bool) *httptest.ResponseRecorder {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req := httptest.NewRequest(method, path, body)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestAdmin_Access tests that the UI requires the admin permission
+func TestAdmin_Access(t *testing.T) {
+	h, _ := newAdmin(t)
+
+	tests := []struct {
+		name         string
+		user         string
+		expectedCode int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"without permission", "alice", http.StatusForbidden},
+		{"admin", "root", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(h, http.MethodGet, "/admin/", tt.user, nil, false)
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+// TestAdmin_Index tests the full page and prefix handling
+func TestAdmin_Index(t *testing.T) {
+	h, _ := newAdmin(t)
+
+	// Mounted at /admin, and behind http.StripPrefix
+	for _, path := range []string{"/admin", "/admin/"} {
+		rec := do(h, http.MethodGet, path, "root", nil, false)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, rec.Code)
+		}
+	}
+	stripped := http.StripPrefix("/admin", h)
+	rec := do(stripped, http.MethodGet, "/admin/", "root", nil, false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("StripPrefix: expected status 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`id="casbin-admin"`,
+		"<td>alice</td><td>editor</td>",
+		"<td>editor</td><td>posts:write</td>",
+		`hx-post="/admin/roles/assign"`,
+		`action="/admin/policies/remove"`,
+		`<option value="posts:write">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q", want)
+		}
+	}
+}
+
+// TestAdmin_Changes tests role and policy changes, toasts and persistence
+func TestAdmin_Changes(t *testing.T) {
+	h, policyPath := newAdmin(t)
+
+	rec := do(h, http.MethodPost, "/admin/roles/assign", "root",
+		url.Values{"user": {"bob"}, "role": {"reader"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("assign: expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Header().Get("HX-Trigger"), "Assigned role reader to bob.") {
+		t.Errorf("expected success toast, got HX-Trigger %q", rec.Header().Get("HX-Trigger"))
+	}
+	if body := rec.Body.String(); strings.Contains(body, "<html") || !strings.Contains(body, "<td>bob</td><td>reader</td>") {
+		t.Errorf("expected content fragment with new assignment, got %s", body)
+	}
+
+	rec = do(h, http.MethodPost, "/admin/policies/add", "root",
+		url.Values{"v0": {"reader"}, "v1": {"posts:write"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("add policy: expected status 200, got %d", rec.Code)
+	}
+
+	rec = do(h, http.MethodPost, "/admin/policies/remove", "root",
+		url.Values{"v0": {"editor"}, "v1": {"posts:write"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("remove policy: expected status 200, got %d", rec.Code)
+	}
+
+	rec = do(h, http.MethodPost, "/admin/roles/revoke", "root",
+		url.Values{"user": {"alice"}, "role": {"editor"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("revoke: expected status 200, got %d", rec.Code)
+	}
+
+	data, err := os.ReadFile(policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "p, admin, casbin:admin\np, reader, posts:read\np, reader, posts:write\ng, root, admin\ng, bob, reader\n"
+	if string(data) != want {
+		t.Errorf("expected policy file\n%s\ngot\n%s", want, data)
+	}
+}
+
+// TestAdmin_ChangesFullPage tests that plain form posts redirect back to
+// the admin page (post/redirect/get)
+func TestAdmin_ChangesFullPage(t *testing.T) {
+	h, _ := newAdmin(t)
+
+	rec := do(h, http.MethodPost, "/admin/roles/assign", "root",
+		url.Values{"user": {"bob"}, "role": {"reader"}}, false)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Location"); got != "/admin/" {
+		t.Errorf("expected redirect to /admin/, got %q", got)
+	}
+
+	rec = do(h, http.MethodGet, "/admin/", "root", nil, false)
+	if !strings.Contains(rec.Body.String(), "<td>bob</td><td>reader</td>") {
+		t.Errorf("expected new assignment after redirect, got %s", rec.Body)
+	}
+}
+
+// TestAdmin_AssignWithoutSchema tests that roles with permissions but no
+// members are offered and assignable without declared roles
+func TestAdmin_AssignWithoutSchema(t *testing.T) {
+	h, _ := newAdminWithSchema(t, casbinx.Schema{})
+
+	rec := do(h, http.MethodGet, "/admin/", "root", nil, false)
+	if !strings.Contains(rec.Body.String(), `<option value="reader">`) {
+		t.Errorf("expected role option for reader, got %s", rec.Body)
+	}
+
+	rec = do(h, http.MethodPost, "/admin/roles/assign", "root",
+		url.Values{"user": {"bob"}, "role": {"reader"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("assign: expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "<td>bob</td><td>reader</td>") {
+		t.Errorf("expected new assignment, got %s", rec.Body)
+	}
+}
+
+// TestAdmin_ChangeErrors tests the status codes of rejected changes
+func TestAdmin_ChangeErrors(t *testing.T) {
+	h, _ := newAdmin(t)
+
+	tests := []struct {
+		name         string
+		path         string
+		form         url.Values
+		expectedCode int
+	}{
+		{"existing rule", "/admin/roles/assign", url.Values{"user": {"alice"}, "role": {"editor"}}, http.StatusConflict},
+		{"missing rule", "/admin/roles/revoke", url.Values{"user": {"bob"}, "role": {"editor"}}, http.StatusNotFound},
+		{"unknown role", "/admin/roles/assign", url.Values{"user": {"bob"}, "role": {"owner"}}, http.StatusUnprocessableEntity},
+		{"unknown action", "/admin/policies/add", url.Values{"v0": {"reader"}, "v1": {"posts:delete"}}, http.StatusUnprocessableEntity},
+		{"empty value", "/admin/policies/add", url.Values{"v0": {"reader"}}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(h, http.MethodPost, tt.path, "root", tt.form, true)
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if rec.Header().Get("HX-Trigger") == "" {
+				t.Error("expected error toast via HX-Trigger")
+			}
+		})
+	}
+}
+
+// TestAdmin_Check tests the "can X do Y" form
+func TestAdmin_Check(t *testing.T) {
+	h, _ := newAdmin(t)
+
+	rec := do(h, http.MethodPost, "/admin/check", "root",
+		url.Values{"r0": {"alice"}, "r1": {"posts:write"}}, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, `<p class="allowed">`) || !strings.Contains(body, "via alice -&gt; editor") {
+		t.Errorf("expected allowed explanation fragment, got %s", body)
+	}
+
+	rec = do(h, http.MethodPost, "/admin/check", "root",
+		url.Values{"r0": {"alice"}, "r1": {"casbin:admin"}}, false)
+	if !strings.Contains(rec.Body.String(), `<p class="denied">`) || !strings.Contains(rec.Body.String(), "<!DOCTYPE html>") {
+		t.Errorf("expected full page with denied explanation, got %s", rec.Body)
+	}
+}
+
+// TestNew_Panics tests that an unprotected UI cannot be created
+func TestNew_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without Subject and Protect")
+		}
+	}()
+	perm := casbinx.NewPerm(nil)
+	New(Config{Perm: perm})
+}
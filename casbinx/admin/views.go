@@ -0,0 +1,254 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+	"github.com/axelrhd/hagg-lib/csrf"
+	"github.com/axelrhd/hagg-lib/handler"
+	"github.com/axelrhd/hagg-lib/hx"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// Element IDs used as htmx targets.
+const (
+	contentID = "casbin-admin"
+	resultID  = "casbin-admin-check"
+)
+
+// DefaultLayout renders a minimal standalone page without scripts: forms
+// post and redirect back, without toasts. Apps should pass their own layout
+// (with htmx, assets.Scripts and styles) instead.
+func DefaultLayout(ctx *handler.Context, title string, content g.Node) g.Node {
+	return g.Group([]g.Node{
+		g.Raw("<!DOCTYPE html>"),
+		h.HTML(
+			h.Head(
+				h.Meta(h.Charset("utf-8")),
+				h.TitleEl(g.Text(title)),
+			),
+			h.Body(
+				h.H1(g.Text(title)),
+				content,
+			),
+		),
+	})
+}
+
+// content renders all admin sections.
+func (a *admin) content(req *http.Request, exp *casbinx.Explanation) g.Node {
+	perm := a.cfg.Perm
+	return h.Div(h.ID(contentID),
+		a.checkSection(req, perm, exp),
+		g.If(hasRoles(perm), a.rolesSection(req, perm)),
+		a.policiesSection(req, perm),
+	)
+}
+
+// checkSection renders the "can X do Y" form.
+func (a *admin) checkSection(req *http.Request, perm *casbinx.Perm, exp *casbinx.Explanation) g.Node {
+	var inputs []g.Node
+	for i, f := range perm.RequestFields() {
+		inputs = append(inputs, textInput(fmt.Sprintf("r%d", i), f))
+	}
+
+	var result g.Node
+	if exp != nil {
+		result = checkResult(exp)
+	}
+
+	return h.Section(
+		h.H2(g.Text("Check access")),
+		h.Form(
+			a.post(req, "/check"),
+			hx.Target("#"+resultID),
+			hx.Swap(hx.InnerHTML),
+			csrfInput(req),
+			g.Group(inputs),
+			h.Button(h.Type("submit"), g.Text("Check")),
+		),
+		h.Div(h.ID(resultID), h.Aria("live", "polite"), result),
+	)
+}
+
+// checkResult renders an explanation.
+func checkResult(exp *casbinx.Explanation) g.Node {
+	class := "denied"
+	if exp.Allowed {
+		class = "allowed"
+	}
+	return h.P(h.Class(class), g.Text(exp.String()))
+}
+
+// rolesSection renders role assignments and the assign form.
+func (a *admin) rolesSection(req *http.Request, perm *casbinx.Perm) g.Node {
+	domains := hasDomains(perm)
+
+	var rows []g.Node
+	grouping, _ := perm.GroupingPolicy()
+	for _, rule := range grouping {
+		domain := ""
+		if domains && len(rule) > 2 {
+			domain = rule[2]
+		}
+		rows = append(rows, h.Tr(
+			h.Td(g.Text(rule[0])),
+			h.Td(g.Text(rule[1])),
+			g.If(domains, h.Td(g.Text(domain))),
+			h.Td(h.Form(
+				a.post(req, "/roles/revoke"),
+				hx.Confirm(fmt.Sprintf("Revoke role %s from %s?", rule[1], rule[0])),
+				csrfInput(req),
+				hiddenInput("user", rule[0]),
+				hiddenInput("role", rule[1]),
+				g.If(domains, hiddenInput("domain", domain)),
+				h.Button(h.Type("submit"), g.Text("Revoke")),
+			)),
+		))
+	}
+
+	var roleOptions []g.Node
+	for _, r := range perm.KnownRoles() {
+		roleOptions = append(roleOptions, h.Option(h.Value(r), g.Text(r)))
+	}
+
+	return h.Section(
+		h.H2(g.Text("Role assignments")),
+		h.Table(
+			h.THead(h.Tr(
+				h.Th(g.Text("Subject")),
+				h.Th(g.Text("Role")),
+				g.If(domains, h.Th(g.Text("Domain"))),
+				h.Th(),
+			)),
+			h.TBody(g.Group(rows)),
+		),
+		h.Form(
+			a.post(req, "/roles/assign"),
+			csrfInput(req),
+			textInput("user", "subject"),
+			h.Select(h.Name("role"), h.Required(), h.Aria("label", "role"), g.Group(roleOptions)),
+			g.If(domains, textInput("domain", "domain")),
+			h.Button(h.Type("submit"), g.Text("Assign role")),
+		),
+	)
+}
+
+// policiesSection renders policies and the add form.
+func (a *admin) policiesSection(req *http.Request, perm *casbinx.Perm) g.Node {
+	fields := policyFields(perm)
+	actions := perm.Schema().Actions
+
+	var head []g.Node
+	for _, f := range fields {
+		head = append(head, h.Th(g.Text(f)))
+	}
+
+	var rows []g.Node
+	policies, _ := perm.Policy()
+	for _, rule := range policies {
+		var cells, hidden []g.Node
+		for i, v := range rule {
+			cells = append(cells, h.Td(g.Text(v)))
+			hidden = append(hidden, hiddenInput(fmt.Sprintf("v%d", i), v))
+		}
+		rows = append(rows, h.Tr(
+			g.Group(cells),
+			h.Td(h.Form(
+				a.post(req, "/policies/remove"),
+				hx.Confirm("Remove policy "+strings.Join(rule, ", ")+"?"),
+				csrfInput(req),
+				g.Group(hidden),
+				h.Button(h.Type("submit"), g.Text("Remove")),
+			)),
+		))
+	}
+
+	var inputs []g.Node
+	for i, f := range fields {
+		name := fmt.Sprintf("v%d", i)
+		if f == "act" && len(actions) > 0 {
+			var opts []g.Node
+			for _, act := range actions {
+				opts = append(opts, h.Option(h.Value(act), g.Text(act)))
+			}
+			inputs = append(inputs, h.Select(h.Name(name), h.Required(), h.Aria("label", f), g.Group(opts)))
+			continue
+		}
+		inputs = append(inputs, textInput(name, f))
+	}
+
+	return h.Section(
+		h.H2(g.Text("Policies")),
+		h.Table(
+			h.THead(h.Tr(g.Group(head), h.Th())),
+			h.TBody(g.Group(rows)),
+		),
+		h.Form(
+			a.post(req, "/policies/add"),
+			csrfInput(req),
+			g.Group(inputs),
+			h.Button(h.Type("submit"), g.Text("Add policy")),
+		),
+	)
+}
+
+// post returns the form attributes for an admin endpoint: a plain form
+// post as fallback and hx-post replacing the admin content.
+func (a *admin) post(req *http.Request, p string) g.Node {
+	return g.Group([]g.Node{
+		h.Method("post"),
+		h.Action(view.URLString(req, a.cfg.Prefix+p)),
+		hx.Post(req, a.cfg.Prefix+p),
+		hx.Target("#" + contentID),
+		hx.Swap(hx.OuterHTML),
+	})
+}
+
+// textInput renders a required text input labeled by placeholder.
+func textInput(name, label string) g.Node {
+	return h.Input(h.Type("text"), h.Name(name), h.Placeholder(label), h.Aria("label", label), h.Required())
+}
+
+// hiddenInput renders a hidden input.
+func hiddenInput(name, value string) g.Node {
+	return h.Input(h.Type("hidden"), h.Name(name), h.Value(value))
+}
+
+// csrfInput renders the CSRF field if the csrf middleware is used.
+func csrfInput(req *http.Request) g.Node {
+	if csrf.Token(req) == "" {
+		return nil
+	}
+	return csrf.Input(req)
+}
+
+// policyFields returns the field names of the policy definition.
+func policyFields(perm *casbinx.Perm) []string {
+	ast, ok := perm.Enforcer().GetModel()["p"]["p"]
+	if !ok {
+		return nil
+	}
+	fields := make([]string, len(ast.Tokens))
+	for i, t := range ast.Tokens {
+		fields[i] = strings.TrimPrefix(t, "p_")
+	}
+	return fields
+}
+
+// hasRoles reports whether the model has a role definition.
+func hasRoles(perm *casbinx.Perm) bool {
+	_, ok := perm.Enforcer().GetModel()["g"]["g"]
+	return ok
+}
+
+// hasDomains reports whether role assignments have a domain (g = _, _, _).
+func hasDomains(perm *casbinx.Perm) bool {
+	ast, ok := perm.Enforcer().GetModel()["g"]["g"]
+	return ok && len(ast.Tokens) > 2
+}
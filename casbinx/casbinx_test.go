@@ -0,0 +1,44 @@
+package casbinx
+
+import (
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// rbacModel is a two-argument RBAC model (sub, act).
+const rbacModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.act == p.act
+`
+
+// rbacPolicy grants "posts:read" to readers and "posts:write" to editors.
+const rbacPolicy = `
+p, reader, posts:read
+p, editor, posts:write
+g, alice, editor
+g, editor, reader
+g, bob, reader
+`
+
+// newEnforcer creates an enforcer from model and policy text (may be empty).
+func newEnforcer(t *testing.T, modelText, policy string) *casbin.Enforcer {
+	t.Helper()
+	e, err := NewStringEnforcer(modelText, policy)
+	if err != nil {
+		t.Fatalf("enforcer: %v", err)
+	}
+	return e
+}
@@ -0,0 +1,38 @@
+// Package casbinxtest provides test helpers for casbinx policies.
+//
+// It is a separate package so that casbinx does not import "testing".
+//
+// # Dependencies
+//
+// Requires: casbinx package, Casbin
+package casbinxtest
+
+import (
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+	"github.com/casbin/casbin/v2"
+)
+
+// TestPolicy checks the cases in a Go test and reports every failing case
+// with the deciding rule:
+//
+//	func TestPolicy(t *testing.T) {
+//	    e, err := casbinx.NewFileEnforcer("model.conf", "policy.csv")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    casbinxtest.TestPolicy(t, e, []casbinx.PolicyCase{
+//	        {Request: []string{"alice", "posts", "write"}, Allow: true},
+//	        {Request: []string{"bob", "posts", "write"}, Allow: false},
+//	    })
+//	}
+//
+// Use casbinx.ReadPolicyCases to keep the cases in a file next to
+// policy.csv (also checked by cmd/casbin-policytest).
+func TestPolicy(t testing.TB, enforcer casbin.IEnforcer, cases []casbinx.PolicyCase) {
+	t.Helper()
+	for _, f := range casbinx.CheckPolicy(enforcer, cases) {
+		t.Error(f.String())
+	}
+}
@@ -0,0 +1,79 @@
+package casbinxtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+)
+
+const rbacModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.act == p.act
+`
+
+const rbacPolicy = `
+p, reader, posts:read
+p, editor, posts:write
+g, alice, editor
+g, editor, reader
+g, bob, reader
+`
+
+// recordingT records errors reported by TestPolicy.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+// TestTestPolicy tests that failing cases are reported with explanations
+func TestTestPolicy(t *testing.T) {
+	e, err := casbinx.NewStringEnforcer(rbacModel, rbacPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &recordingT{}
+	TestPolicy(rec, e, []casbinx.PolicyCase{
+		{Request: []string{"alice", "posts:read"}, Allow: true},
+		{Request: []string{"bob", "posts:write"}, Allow: false},
+		{Request: []string{"alice", "posts:write"}, Allow: false, Line: 3},
+		{Request: []string{"bob", "posts:delete"}, Allow: true},
+		{Request: []string{"bob", "posts", "read"}, Allow: true},
+	})
+
+	if len(rec.errors) != 3 {
+		t.Fatalf("expected 3 failures, got %d: %q", len(rec.errors), rec.errors)
+	}
+	expected := []string{
+		"line 3: alice, posts:write, deny: alice posts:write: allowed by [editor posts:write] via alice -> editor",
+		"bob, posts:delete, allow: bob posts:delete: denied (no matching rule)",
+	}
+	for i, want := range expected {
+		if rec.errors[i] != want {
+			t.Errorf("expected %q, got %q", want, rec.errors[i])
+		}
+	}
+	if !strings.Contains(rec.errors[2], casbinx.ErrShapeMismatch.Error()) {
+		t.Errorf("expected arity error, got %q", rec.errors[2])
+	}
+}
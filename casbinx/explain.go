@@ -0,0 +1,120 @@
+package casbinx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// Explanation describes why a request was allowed or denied.
+type Explanation struct {
+	Request []string // Request values, e.g. [alice posts:write]
+	Allowed bool     // Decision
+
+	// Rule is the policy rule that decided the request, e.g.
+	// [editor posts:write]. Empty if no rule matched (default deny).
+	Rule []string
+
+	// Roles is the role chain from the subject to the subject of Rule,
+	// e.g. [alice editor]. Empty if Rule applies to the subject directly
+	// or no rule matched.
+	Roles []string
+}
+
+// String returns a human-readable explanation, e.g.
+// "alice posts:write: allowed by [editor posts:write] via alice -> editor".
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	sb.WriteString(strings.Join(e.Request, " "))
+	if e.Allowed {
+		sb.WriteString(": allowed")
+	} else {
+		sb.WriteString(": denied")
+	}
+	if len(e.Rule) == 0 {
+		sb.WriteString(" (no matching rule)")
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, " by [%s]", strings.Join(e.Rule, " "))
+	if len(e.Roles) > 0 {
+		fmt.Fprintf(&sb, " via %s", strings.Join(e.Roles, " -> "))
+	}
+	return sb.String()
+}
+
+// Explain evaluates a request and reports the deciding rule and role chain
+// (via Casbin's EnforceEx). rvals must match the request definition of the
+// model, e.g. Explain("alice", "posts:write") for r = sub, act or
+// Explain("alice", "tenant1", "posts", "write") for r = sub, dom, obj, act.
+//
+// Use it to debug denials or to show admins why access was granted:
+//
+//	exp, err := perm.Explain(user, "posts:write")
+//	if err != nil {
+//	    return err
+//	}
+//	logger.Info("authorization", "decision", exp.String())
+func (p *Perm) Explain(rvals ...string) (*Explanation, error) {
+	fields := p.RequestFields()
+	if len(rvals) != len(fields) {
+		return nil, fmt.Errorf("casbinx: Explain got %d values, but the model defines r = %s: %w",
+			len(rvals), strings.Join(fields, ", "), ErrShapeMismatch)
+	}
+
+	args := make([]any, len(rvals))
+	for i, v := range rvals {
+		args[i] = v
+	}
+
+	e, done := p.read()
+	defer done()
+
+	allowed, rule, err := e.EnforceEx(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{Request: rvals, Allowed: allowed, Rule: rule}
+	if len(rule) > 0 && len(rvals) > 0 && rule[0] != rvals[0] {
+		var domain []string
+		if p.Shape() == ShapeSubDomObjAct {
+			domain = []string{rvals[1]}
+		}
+		exp.Roles = roleChain(e, rvals[0], rule[0], domain)
+	}
+	return exp, nil
+}
+
+// roleChain returns the shortest path from sub to role in the role graph
+// (breadth-first), or nil if role is not reachable (e.g. matched by a
+// pattern instead of a role).
+func roleChain(e casbin.IEnforcer, sub, role string, domain []string) []string {
+	prev := map[string]string{sub: ""}
+	queue := []string{sub}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur == role {
+			var chain []string
+			for n := cur; n != ""; n = prev[n] {
+				chain = append([]string{n}, chain...)
+			}
+			return chain
+		}
+
+		roles, err := e.GetRolesForUser(cur, domain...)
+		if err != nil {
+			return nil
+		}
+		for _, r := range roles {
+			if _, seen := prev[r]; !seen {
+				prev[r] = cur
+				queue = append(queue, r)
+			}
+		}
+	}
+	return nil
+}
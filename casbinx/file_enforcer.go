@@ -13,6 +13,12 @@
 //	    log.Fatal(err)
 //	}
 //
+// Use NewFSEnforcer for files embedded in the binary, optionally
+// overridden by a site-specific directory, and NewStringEnforcer or
+// NewReaderEnforcer for model and policy from other sources:
+//
+//	e, err := casbinx.NewFSEnforcer(casbinx.Overlay(authzFS, cfg.AuthzDir), "model.conf", "policy.csv")
+//
 // # Permission Checking
 //
 // Use MustHavePermission for simple permission checks:
@@ -21,6 +27,87 @@
 //	    return errors.New("permission denied")
 //	}
 //
+// # Errors and Explanations
+//
+// Can, CanOn, CanIn and the Get* queries treat errors as "denied" or
+// "empty". Check, CheckOn, CheckIn and the queries without Get prefix
+// (RolesForUser, Policy, ...) return them instead. Explain reports the
+// deciding rule and role chain:
+//
+//	exp, _ := perm.Explain("alice", "posts:write")
+//	fmt.Println(exp)  // alice posts:write: allowed by [editor posts:write] via alice -> editor
+//
+// # Models With Resources and Domains
+//
+// Perm.Can expects r = sub, act. For RBAC with resources use CanOn
+// (r = sub, obj, act), for multi-tenant models CanIn (r = sub, dom, obj, act).
+// Checks against a model of another shape are denied; RequireShape reports
+// the mismatch at startup:
+//
+//	perm := casbinx.NewPerm(enforcer)
+//	if err := perm.RequireShape(casbinx.ShapeSubDomObjAct); err != nil {
+//	    return err
+//	}
+//	perm.CanIn("tenant1", "alice", "posts", "write")
+//
+// # Hot Reload
+//
+// A Perm with loader (NewFilePerm, NewReloadablePerm) swaps its enforcer
+// atomically on Reload, so policies can be reloaded while requests are
+// being checked. Without a loader, Reload reloads the enforcer in place
+// (use a SyncedEnforcer for concurrent reloads). Watch reloads on file
+// changes:
+//
+//	perm, err := casbinx.NewFilePerm("model.conf", "policy.csv")
+//	if err != nil {
+//	    return err
+//	}
+//	go perm.Watch(ctx, casbinx.WatchConfig{Files: []string{"model.conf", "policy.csv"}})
+//
+// Invalid files are rejected and the previous policy stays active.
+//
+// # Managing Policies
+//
+// AddPolicy, RemovePolicy, AssignRole and RevokeRole validate changes
+// against the model and an optional Schema and save them atomically to
+// the policy file, keeping comments and ordering:
+//
+//	perm.SetSchema(casbinx.Schema{Actions: []string{"posts:read", "posts:write"}})
+//	if err := perm.AssignRole("bob", "editor"); err != nil {
+//	    return err  // e.g. ErrUnknownRole
+//	}
+//
+// # Protecting Routes
+//
+// Use Middleware instead of checking permissions at the top of every handler:
+//
+//	r.With(casbinx.Middleware(perm, userFromSession, casbinx.Action("posts:write"))).
+//	    Post("/posts", wrapper.Wrap(posts.Create))
+//
+// Anonymous users get 401, denied users 403, both through the HTMX-aware
+// error path of the handler package.
+//
+// # Views
+//
+// If, IfCan and Menu hide buttons and menu entries the user cannot use
+// (WithSubject stores the subject for pages without Middleware):
+//
+//	casbinx.IfCan(req, perm, "posts:write", newPostButton)
+//	casbinx.Menu(req, perm, casbinx.MenuItem{Label: "Posts", Path: "/posts", Action: "posts:read"})
+//
+// # Testing Policies
+//
+// TestPolicy checks expected decisions in a Go test; ReadPolicyCases reads
+// them from a CSV or JSON file ("alice, posts:write, allow") that
+// cmd/casbin-policytest runs as well. Lint reports rules that Casbin
+// accepts silently but that are likely mistakes (cmd/casbin-lint):
+//
+//	cases, err := casbinx.ReadPolicyCases("policy_test.csv")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	casbinx.TestPolicy(t, enforcer, cases)
+//
 // # Design Philosophy
 //
 // This package is intentionally minimal:
@@ -31,7 +118,7 @@
 //
 // # Dependencies
 //
-// Requires: github.com/casbin/casbin/v2
+// Requires: github.com/casbin/casbin/v2, handler package (Middleware), view package and gomponents (views)
 package casbinx
 
 import (
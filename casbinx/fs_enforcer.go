@@ -0,0 +1,143 @@
+package casbinx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// NewFSEnforcer creates a Casbin enforcer from a model and policy file in
+// fsys, e.g. files embedded in the binary:
+//
+//	//go:embed authz/model.conf authz/policy.csv
+//	var authzFS embed.FS
+//
+//	e, err := casbinx.NewFSEnforcer(authzFS, "authz/model.conf", "authz/policy.csv")
+//
+// Combine with Overlay to let a site-specific directory override the
+// embedded defaults.
+//
+// The policy is read once; changes made through the enforcer stay in
+// memory (see NewFileEnforcer for a persistent policy file).
+func NewFSEnforcer(fsys fs.FS, modelPath, policyPath string) (*casbin.Enforcer, error) {
+	modelText, err := fs.ReadFile(fsys, modelPath)
+	if err != nil {
+		return nil, err
+	}
+	policyText, err := fs.ReadFile(fsys, policyPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewStringEnforcer(string(modelText), string(policyText))
+}
+
+// NewStringEnforcer creates a Casbin enforcer from model and policy text
+// (e.g. from configuration or tests). The policy may be empty.
+//
+// Unlike Casbin's string adapter, malformed policy lines are reported
+// with their line number instead of being skipped.
+func NewStringEnforcer(modelText, policyText string) (*casbin.Enforcer, error) {
+	m, err := model.NewModelFromString(modelText)
+	if err != nil {
+		return nil, err
+	}
+	return casbin.NewEnforcer(m, &textAdapter{text: policyText})
+}
+
+// NewReaderEnforcer creates a Casbin enforcer from model and policy readers.
+func NewReaderEnforcer(modelReader, policyReader io.Reader) (*casbin.Enforcer, error) {
+	modelText, err := io.ReadAll(modelReader)
+	if err != nil {
+		return nil, err
+	}
+	policyText, err := io.ReadAll(policyReader)
+	if err != nil {
+		return nil, err
+	}
+	return NewStringEnforcer(string(modelText), string(policyText))
+}
+
+// Overlay returns a file system that serves files from the directory dir
+// if they exist there and from base otherwise. Use it to override embedded
+// defaults with site-specific files:
+//
+//	fsys := casbinx.Overlay(authzFS, "/etc/myapp/authz")
+//	e, err := casbinx.NewFSEnforcer(fsys, "model.conf", "policy.csv")
+//
+// An override replaces the whole file, so /etc/myapp/authz/policy.csv
+// replaces the embedded policy while the embedded model is still used.
+// An empty dir disables the override.
+func Overlay(base fs.FS, dir string) fs.FS {
+	if dir == "" {
+		return base
+	}
+	return &overlayFS{base: base, override: os.DirFS(dir)}
+}
+
+// overlayFS serves files from override, falling back to base.
+type overlayFS struct {
+	base     fs.FS
+	override fs.FS
+}
+
+// Open implements fs.FS.
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.override.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return o.base.Open(name)
+}
+
+// textAdapter is a read-only policy adapter for CSV text.
+// Changes made through the enforcer stay in memory.
+type textAdapter struct {
+	text string
+}
+
+// errNotImplemented tells Casbin to keep changes in memory only.
+var errNotImplemented = errors.New("not implemented")
+
+// LoadPolicy loads all rules, skipping empty lines and comments.
+func (a *textAdapter) LoadPolicy(m model.Model) error {
+	for i, line := range strings.Split(a.text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := persist.LoadPolicyLine(line, m); err != nil {
+			return fmt.Errorf("casbinx: policy line %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// SavePolicy is not supported.
+func (a *textAdapter) SavePolicy(model.Model) error {
+	return errors.New("casbinx: policy from text or fs.FS is read-only")
+}
+
+// AddPolicy keeps the rule in memory.
+func (a *textAdapter) AddPolicy(string, string, []string) error {
+	return errNotImplemented
+}
+
+// RemovePolicy keeps the change in memory.
+func (a *textAdapter) RemovePolicy(string, string, []string) error {
+	return errNotImplemented
+}
+
+// RemoveFilteredPolicy keeps the change in memory.
+func (a *textAdapter) RemoveFilteredPolicy(string, string, int, ...string) error {
+	return errNotImplemented
+}
@@ -0,0 +1,69 @@
+package casbinx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestNewFSEnforcer tests loading from an fs.FS with an override directory
+func TestNewFSEnforcer(t *testing.T) {
+	embedded := fstest.MapFS{
+		"model.conf": {Data: []byte(rbacModel)},
+		"policy.csv": {Data: []byte("# defaults\np, reader, posts:read\n\ng, bob, reader\n")},
+	}
+
+	e, err := NewFSEnforcer(embedded, "model.conf", "policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := e.Enforce("bob", "posts:read"); !ok {
+		t.Error("bob should read with the embedded policy")
+	}
+
+	// Without override files, the embedded defaults are used
+	dir := t.TempDir()
+	if _, err := NewFSEnforcer(Overlay(embedded, dir), "model.conf", "policy.csv"); err != nil {
+		t.Fatalf("overlay without overrides: %v", err)
+	}
+
+	// The site policy replaces the embedded one, the model is still embedded
+	os.WriteFile(filepath.Join(dir, "policy.csv"), []byte("p, reader, posts:write\r\ng, carol, reader\r\n"), 0o644)
+	e, err = NewFSEnforcer(Overlay(embedded, dir), "model.conf", "policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := e.Enforce("carol", "posts:write"); !ok {
+		t.Error("carol should write with the site policy")
+	}
+	if ok, _ := e.Enforce("bob", "posts:read"); ok {
+		t.Error("embedded policy should be replaced")
+	}
+}
+
+// TestNewStringEnforcer tests empty policies and line errors
+func TestNewStringEnforcer(t *testing.T) {
+	if _, err := NewStringEnforcer(rbacModel, ""); err != nil {
+		t.Errorf("empty policy should be valid: %v", err)
+	}
+
+	_, err := NewStringEnforcer(rbacModel, "p, reader, posts:read\nx, broken\n")
+	if err == nil || !strings.Contains(err.Error(), "policy line 2") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+
+	e, err := NewReaderEnforcer(strings.NewReader(rbacModel), strings.NewReader(rbacPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := e.Enforce("alice", "posts:write"); !ok {
+		t.Error("alice should write")
+	}
+
+	// Changes stay in memory
+	if _, err := e.AddPolicy("bob", "posts:delete"); err != nil {
+		t.Errorf("in-memory change failed: %v", err)
+	}
+}
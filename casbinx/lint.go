@@ -0,0 +1,338 @@
+package casbinx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// LintKind classifies a lint problem.
+type LintKind string
+
+// Lint problem kinds.
+const (
+	// LintUngrantedRole: a role is assigned but neither it nor any role it
+	// inherits is granted a permission.
+	LintUngrantedRole LintKind = "ungranted-role"
+
+	// LintCycle: roles inherit from each other in a cycle.
+	LintCycle LintKind = "cycle"
+
+	// LintDuplicate: the same rule appears more than once.
+	LintDuplicate LintKind = "duplicate"
+
+	// LintNoRole: a subject is granted permissions directly but is neither
+	// a role nor assigned one.
+	LintNoRole LintKind = "no-role"
+
+	// LintUnknownAction: a rule grants an action that the schema does not
+	// declare.
+	LintUnknownAction LintKind = "unknown-action"
+
+	// LintShadowed: a rule has no effect because another rule already
+	// grants the same (via an inherited role).
+	LintShadowed LintKind = "shadowed"
+)
+
+// LintProblem is a problem found by Lint.
+type LintProblem struct {
+	// Line in the policy file (1-based).
+	Line int
+
+	// Rule including the ptype, e.g. [p editor posts:write].
+	Rule []string
+
+	Kind    LintKind
+	Message string
+}
+
+// String formats the problem as "line 3: duplicate: ...".
+func (p LintProblem) String() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Kind, p.Message)
+}
+
+// Lint checks a policy file against model m and reports problems that
+// Casbin accepts silently: roles without permissions, role cycles,
+// duplicate and shadowed rules, direct grants to subjects without roles
+// and, if the schema declares actions, undeclared actions.
+//
+// The schema's roles count as roles even if nobody is assigned to them;
+// declare them to avoid no-role reports for unassigned roles.
+//
+// Only the "p" and "g" definitions are checked. Matchers are not
+// evaluated, so rules shadowed by wildcards or pattern matching are not
+// detected.
+//
+// Problems are sorted by line.
+func Lint(m model.Model, f *PolicyFile, schema Schema) []LintProblem {
+	l := newLinter(m, f, schema)
+	l.duplicates()
+	l.cycles()
+	l.ungrantedRoles()
+	l.noRoles()
+	l.unknownActions()
+	l.shadowed()
+
+	slices.SortStableFunc(l.problems, func(a, b LintProblem) int {
+		return a.Line - b.Line
+	})
+	return l.problems
+}
+
+// lintRule is a rule of the policy file with its line number.
+type lintRule struct {
+	line int
+	rule []string // including ptype
+}
+
+// linter holds the parsed policy for the checks.
+type linter struct {
+	schema   Schema
+	pTokens  []string
+	domains  bool       // g = _, _, _
+	domIndex int        // index of p_dom in p rules, -1 if none
+	policies []lintRule // unique p rules
+	groups   []lintRule // unique g rules
+	all      []lintRule // all rules in file order
+	roles    map[string]bool
+	problems []LintProblem
+}
+
+// newLinter collects the rules of f.
+func newLinter(m model.Model, f *PolicyFile, schema Schema) *linter {
+	l := &linter{schema: schema, domIndex: -1, roles: map[string]bool{}}
+	if ast, ok := m["p"]["p"]; ok {
+		l.pTokens = ast.Tokens
+		l.domIndex = slices.Index(ast.Tokens, "p_dom")
+	}
+	if ast, ok := m["g"]["g"]; ok {
+		l.domains = len(ast.Tokens) > 2
+	}
+
+	seen := map[string]bool{}
+	for i, pl := range f.lines {
+		if pl.rule == nil {
+			continue
+		}
+		r := lintRule{line: i + 1, rule: pl.rule}
+		l.all = append(l.all, r)
+
+		key := strings.Join(pl.rule, "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		switch {
+		case pl.rule[0] == "p" && len(pl.rule) == len(l.pTokens)+1:
+			l.policies = append(l.policies, r)
+		case pl.rule[0] == "g" && len(pl.rule) >= 3:
+			l.groups = append(l.groups, r)
+			l.roles[pl.rule[2]] = true
+		}
+	}
+	for _, role := range schema.Roles {
+		l.roles[role] = true
+	}
+	return l
+}
+
+// report adds a problem.
+func (l *linter) report(r lintRule, kind LintKind, format string, args ...any) {
+	l.problems = append(l.problems, LintProblem{
+		Line:    r.line,
+		Rule:    slices.Clone(r.rule),
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// duplicates reports rules that appear more than once.
+func (l *linter) duplicates() {
+	first := map[string]int{}
+	for _, r := range l.all {
+		key := strings.Join(r.rule, "\x00")
+		if line, ok := first[key]; ok {
+			l.report(r, LintDuplicate, "%s (first on line %d)", formatPolicyLine(r.rule), line)
+			continue
+		}
+		first[key] = r.line
+	}
+}
+
+// groupDomain returns the domain of a g rule ("" without domains).
+func (l *linter) groupDomain(rule []string) string {
+	if l.domains && len(rule) > 3 {
+		return rule[3]
+	}
+	return ""
+}
+
+// policyDomain returns the domain of a p rule ("" without domains).
+func (l *linter) policyDomain(rule []string) string {
+	if l.domains && l.domIndex >= 0 {
+		return rule[l.domIndex+1]
+	}
+	return ""
+}
+
+// parents returns the roles sub directly inherits in domain, skipping the
+// g rule at line skip.
+func (l *linter) parents(sub, domain string, skip int) []string {
+	var roles []string
+	for _, g := range l.groups {
+		if g.line != skip && g.rule[1] == sub && l.groupDomain(g.rule) == domain {
+			roles = append(roles, g.rule[2])
+		}
+	}
+	return roles
+}
+
+// ancestors returns all roles sub inherits in domain (transitively),
+// skipping the g rule at line skip.
+func (l *linter) ancestors(sub, domain string, skip int) []string {
+	visited := map[string]bool{sub: true}
+	var result []string
+	queue := []string{sub}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, role := range l.parents(cur, domain, skip) {
+			if visited[role] {
+				continue
+			}
+			visited[role] = true
+			result = append(result, role)
+			queue = append(queue, role)
+		}
+	}
+	return result
+}
+
+// cycles reports role inheritance cycles, once per cycle.
+func (l *linter) cycles() {
+	reported := map[string]bool{}
+	for _, g := range l.groups {
+		sub, role, domain := g.rule[1], g.rule[2], l.groupDomain(g.rule)
+		path := l.path(role, sub, domain, map[string]bool{})
+		if path == nil {
+			continue
+		}
+		cycle := append([]string{sub}, path...)
+
+		// Normalize to the same rotation so each cycle is reported once
+		members := slices.Clone(cycle[:len(cycle)-1])
+		slices.Sort(members)
+		key := domain + "\x00" + strings.Join(members, "\x00")
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		msg := strings.Join(cycle, " -> ")
+		if domain != "" {
+			msg += " in domain " + domain
+		}
+		l.report(g, LintCycle, "%s", msg)
+	}
+}
+
+// path returns a role path from -> ... -> to, or nil.
+func (l *linter) path(from, to, domain string, visited map[string]bool) []string {
+	if from == to {
+		return []string{to}
+	}
+	if visited[from] {
+		return nil
+	}
+	visited[from] = true
+	for _, role := range l.parents(from, domain, 0) {
+		if p := l.path(role, to, domain, visited); p != nil {
+			return append([]string{from}, p...)
+		}
+	}
+	return nil
+}
+
+// ungrantedRoles reports assigned roles without any permission.
+func (l *linter) ungrantedRoles() {
+	granted := map[string]bool{}
+	for _, p := range l.policies {
+		granted[p.rule[1]] = true
+	}
+
+	reported := map[string]bool{}
+	for _, g := range l.groups {
+		role, domain := g.rule[2], l.groupDomain(g.rule)
+		if reported[role] || granted[role] {
+			continue
+		}
+		if slices.ContainsFunc(l.ancestors(role, domain, 0), func(r string) bool { return granted[r] }) {
+			continue
+		}
+		reported[role] = true
+		l.report(g, LintUngrantedRole, "role %q is assigned but has no permissions", role)
+	}
+}
+
+// noRoles reports subjects with direct permissions that are neither roles
+// nor assigned a role.
+func (l *linter) noRoles() {
+	members := map[string]bool{}
+	for _, g := range l.groups {
+		members[g.rule[1]] = true
+	}
+
+	reported := map[string]bool{}
+	for _, p := range l.policies {
+		sub := p.rule[1]
+		if l.roles[sub] || members[sub] || reported[sub] {
+			continue
+		}
+		reported[sub] = true
+		l.report(p, LintNoRole, "subject %q has permissions but is neither a role nor assigned one", sub)
+	}
+}
+
+// unknownActions reports actions not declared in the schema.
+func (l *linter) unknownActions() {
+	if len(l.schema.Actions) == 0 || len(l.pTokens) == 0 {
+		return
+	}
+	i := actionIndex(l.pTokens) + 1
+	for _, p := range l.policies {
+		if act := p.rule[i]; !slices.Contains(l.schema.Actions, act) {
+			l.report(p, LintUnknownAction, "action %q is not declared", act)
+		}
+	}
+}
+
+// shadowed reports p rules already granted through an inherited role and
+// g rules already implied by other assignments.
+func (l *linter) shadowed() {
+	for _, p := range l.policies {
+		sub, rest := p.rule[1], p.rule[2:]
+		for _, role := range l.ancestors(sub, l.policyDomain(p.rule), 0) {
+			i := slices.IndexFunc(l.policies, func(o lintRule) bool {
+				return o.rule[1] == role && slices.Equal(o.rule[2:], rest)
+			})
+			if i >= 0 {
+				l.report(p, LintShadowed, "%s is already granted via role %q (line %d)",
+					formatPolicyLine(p.rule), role, l.policies[i].line)
+				break
+			}
+		}
+	}
+
+	for _, g := range l.groups {
+		sub, role := g.rule[1], g.rule[2]
+		if sub == role {
+			continue
+		}
+		if slices.Contains(l.ancestors(sub, l.groupDomain(g.rule), g.line), role) {
+			l.report(g, LintShadowed, "%s is already implied by other role assignments", formatPolicyLine(g.rule))
+		}
+	}
+}
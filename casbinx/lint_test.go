@@ -0,0 +1,131 @@
+package casbinx
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// lint runs Lint on policy text.
+func lint(t *testing.T, modelText, policy string, schema Schema) []LintProblem {
+	t.Helper()
+	m, err := model.NewModelFromString(modelText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "policy.csv")
+	if err := os.WriteFile(path, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadPolicyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Lint(m, f, schema)
+}
+
+// TestLint_Clean tests that a correct policy has no problems
+func TestLint_Clean(t *testing.T) {
+	problems := lint(t, rbacModel, rbacPolicy, Schema{Actions: []string{"posts:read", "posts:write"}})
+	if len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+// TestLint tests each problem kind
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		schema   Schema
+		expected []string
+	}{
+		{
+			"duplicate",
+			"p, reader, posts:read\np, reader, posts:read\ng, bob, reader\n",
+			Schema{},
+			[]string{"line 2: duplicate: p, reader, posts:read (first on line 1)"},
+		},
+		{
+			"cycle",
+			"p, a, x\ng, a, b\ng, b, c\ng, c, a\ng, bob, a\n",
+			Schema{},
+			[]string{"line 2: cycle: a -> b -> c -> a"},
+		},
+		{
+			"ungranted role",
+			"p, reader, posts:read\ng, bob, reader\ng, carol, auditor\n",
+			Schema{},
+			[]string{`line 3: ungranted-role: role "auditor" is assigned but has no permissions`},
+		},
+		{
+			"inherited grant is no ungranted role",
+			"p, reader, posts:read\ng, editor, reader\ng, bob, editor\n",
+			Schema{},
+			nil,
+		},
+		{
+			"no role",
+			"p, reader, posts:read\ng, bob, reader\np, carol, posts:write\n",
+			Schema{},
+			[]string{`line 3: no-role: subject "carol" has permissions but is neither a role nor assigned one`},
+		},
+		{
+			"declared role is a role",
+			"p, admin, posts:write\n",
+			Schema{Roles: []string{"admin"}},
+			nil,
+		},
+		{
+			"unknown action",
+			"p, reader, posts:read\np, reader, posts:raed\ng, bob, reader\n",
+			Schema{Actions: []string{"posts:read"}},
+			[]string{`line 2: unknown-action: action "posts:raed" is not declared`},
+		},
+		{
+			"shadowed policy",
+			"p, reader, posts:read\np, editor, posts:read\ng, editor, reader\ng, bob, editor\n",
+			Schema{},
+			[]string{`line 2: shadowed: p, editor, posts:read is already granted via role "reader" (line 1)`},
+		},
+		{
+			"shadowed assignment",
+			"p, reader, posts:read\ng, editor, reader\ng, bob, editor\ng, bob, reader\n",
+			Schema{},
+			[]string{"line 4: shadowed: g, bob, reader is already implied by other role assignments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range lint(t, rbacModel, tt.policy, tt.schema) {
+				got = append(got, p.String())
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestLint_Domains tests that role inheritance is checked per domain
+func TestLint_Domains(t *testing.T) {
+	policy := `p, admin, tenant1, posts, write
+p, admin, tenant2, posts, write
+g, alice, admin, tenant1
+g, alice, admin, tenant2
+g, admin, alice, tenant2
+`
+	var got []string
+	for _, p := range lint(t, domainModel, policy, Schema{}) {
+		got = append(got, p.String())
+	}
+	expected := []string{"line 4: cycle: alice -> admin -> alice in domain tenant2"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
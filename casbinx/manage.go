@@ -0,0 +1,317 @@
+package casbinx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// Schema declares the roles and actions an app knows. Policy changes
+// through Perm are validated against it, so a typo in an admin form
+// cannot grant a nonexistent action.
+type Schema struct {
+	// Roles that may be assigned and granted permissions.
+	// Empty: subjects of p rules and roles assigned in g rules.
+	Roles []string
+
+	// Actions that may be granted. Empty: no action validation.
+	Actions []string
+}
+
+// Policy management errors.
+var (
+	ErrInvalidRule   = errors.New("casbinx: invalid rule")
+	ErrUnknownRole   = errors.New("casbinx: unknown role")
+	ErrUnknownAction = errors.New("casbinx: unknown action")
+	ErrRuleExists    = errors.New("casbinx: rule already exists")
+	ErrRuleNotFound  = errors.New("casbinx: rule not found")
+)
+
+// SetSchema sets the roles and actions used to validate policy changes.
+// Returns self for method chaining.
+func (p *Perm) SetSchema(schema Schema) *Perm {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.schema = schema
+	return p
+}
+
+// Schema returns the schema set with SetSchema.
+func (p *Perm) Schema() Schema {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.schema
+}
+
+// SetPolicyFile sets the CSV file that policy changes are saved to
+// atomically, keeping comments and ordering (NewFilePerm sets it
+// automatically). Without a file, changes are saved with the adapter's
+// SavePolicy; adapters without SavePolicy support (NewStringEnforcer,
+// NewFSEnforcer) keep them in memory only.
+// Returns self for method chaining.
+func (p *Perm) SetPolicyFile(path string) *Perm {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policyPath = path
+	return p
+}
+
+// KnownRoles returns the roles of the schema, or - without declared
+// roles - the subjects of p rules and the roles assigned in g rules,
+// sorted. A role with permissions but no members is assignable this way;
+// declare Schema.Roles to keep users with direct grants out of the list.
+func (p *Perm) KnownRoles() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.knownRoles(p.Enforcer())
+}
+
+// knownRoles implements KnownRoles; p.mu must be held.
+func (p *Perm) knownRoles(e casbin.IEnforcer) []string {
+	if len(p.schema.Roles) > 0 {
+		return slices.Sorted(slices.Values(p.schema.Roles))
+	}
+	var roles []string
+	if rules, err := e.GetPolicy(); err == nil {
+		for _, r := range rules {
+			roles = append(roles, r[0])
+		}
+	}
+	if rules, err := e.GetGroupingPolicy(); err == nil {
+		for _, r := range rules {
+			roles = append(roles, r[1])
+		}
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// AddPolicy grants a permission, e.g. AddPolicy("editor", "posts:write")
+// or AddPolicy("editor", "tenant1", "posts", "write") for domain models.
+//
+// The rule must match the policy definition of the model; the subject
+// must be a known role if the schema declares roles, and the action must
+// be declared if the schema declares actions. The change is saved (see
+// SetPolicyFile) before it becomes active.
+func (p *Perm) AddPolicy(rule ...string) error {
+	return p.change("AddPolicy", "p", rule, true)
+}
+
+// RemovePolicy revokes a permission. Returns ErrRuleNotFound if the rule
+// does not exist.
+func (p *Perm) RemovePolicy(rule ...string) error {
+	return p.change("RemovePolicy", "p", rule, false)
+}
+
+// AssignRole assigns a known role to user (with domain for multi-tenant
+// models). Returns ErrUnknownRole for roles that are neither declared nor
+// assigned in the policy (see KnownRoles).
+func (p *Perm) AssignRole(user, role string, domain ...string) error {
+	return p.change("AssignRole", "g", append([]string{user, role}, domain...), true)
+}
+
+// RevokeRole removes a role from user. Returns ErrRuleNotFound if the
+// user does not have the role.
+func (p *Perm) RevokeRole(user, role string, domain ...string) error {
+	return p.change("RevokeRole", "g", append([]string{user, role}, domain...), false)
+}
+
+// change validates, applies and saves a rule change.
+//
+// Without a loader (NewPerm) the change is applied to the current enforcer
+// in place, so matching functions and role managers registered on it are
+// kept; a failed save undoes it. With a loader a fresh enforcer is built,
+// takes over the active policy and gets the change; it is swapped in after
+// saving, so concurrent checks never see a half-applied change and a
+// failed save leaves the active policy untouched.
+func (p *Perm) change(check, ptype string, rule []string, add bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ref := p.current.Load()
+	if p.load == nil {
+		return p.changeInPlace(ref, ptype, rule, add)
+	}
+
+	next, err := p.load()
+	if err != nil {
+		return err
+	}
+	if err := shapeOf(next, check, detectShape(ref.fields)); err != nil {
+		return err
+	}
+	if err := carryPolicy(next, ref.e); err != nil {
+		return err
+	}
+	if add {
+		if err := p.validate(next, ptype, rule); err != nil {
+			return err
+		}
+	}
+
+	// save persists the change explicitly
+	next.EnableAutoSave(false)
+	if err := apply(next, ptype, rule, add); err != nil {
+		return err
+	}
+	if err := p.save(next, ptype, rule, add); err != nil {
+		return fmt.Errorf("casbinx: save policy: %w", err)
+	}
+
+	p.current.Store(newEnforcerRef(next))
+	return nil
+}
+
+// carryPolicy replaces the policy of next with the policy of current, so
+// changes kept in memory only are not lost when the loader rebuilds.
+func carryPolicy(next, current casbin.IEnforcer) error {
+	m := next.GetModel()
+	m.ClearPolicy()
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range current.GetModel()[sec] {
+			if _, ok := m[sec][ptype]; !ok {
+				return fmt.Errorf("casbinx: loaded model has no %q definition, Reload first", ptype)
+			}
+			if err := m.AddPolicies(sec, ptype, ast.Policy); err != nil {
+				return err
+			}
+		}
+	}
+	return next.BuildRoleLinks()
+}
+
+// changeInPlace applies a change to the enforcer of ref and undoes it if
+// saving fails; p.mu must be held.
+func (p *Perm) changeInPlace(ref *enforcerRef, ptype string, rule []string, add bool) error {
+	if add {
+		if err := p.validate(ref.e, ptype, rule); err != nil {
+			return err
+		}
+	}
+
+	if err := p.applyGuarded(ref, ptype, rule, add); err != nil {
+		return err
+	}
+	if err := p.save(ref.e, ptype, rule, add); err != nil {
+		if undoErr := p.applyGuarded(ref, ptype, rule, !add); undoErr != nil {
+			return fmt.Errorf("casbinx: save policy: %w (undo failed: %v)", err, undoErr)
+		}
+		return fmt.Errorf("casbinx: save policy: %w", err)
+	}
+	return nil
+}
+
+// applyGuarded is apply under Perm.rw for enforcers without own lock.
+func (p *Perm) applyGuarded(ref *enforcerRef, ptype string, rule []string, add bool) error {
+	if ref.guarded {
+		p.rw.Lock()
+		defer p.rw.Unlock()
+	}
+	return apply(ref.e, ptype, rule, add)
+}
+
+// apply adds or removes a rule. Returns ErrRuleExists or ErrRuleNotFound
+// if the rule does not change the policy.
+func apply(e casbin.IEnforcer, ptype string, rule []string, add bool) error {
+	params := make([]any, len(rule))
+	for i, v := range rule {
+		params[i] = v
+	}
+
+	var changed bool
+	var err error
+	switch {
+	case ptype == "p" && add:
+		changed, err = e.AddPolicy(params...)
+	case ptype == "p":
+		changed, err = e.RemovePolicy(params...)
+	case add:
+		changed, err = e.AddGroupingPolicy(params...)
+	default:
+		changed, err = e.RemoveGroupingPolicy(params...)
+	}
+	if err != nil {
+		return err
+	}
+	if !changed && add {
+		return fmt.Errorf("%w: %s, %v", ErrRuleExists, ptype, rule)
+	}
+	if !changed {
+		return fmt.Errorf("%w: %s, %v", ErrRuleNotFound, ptype, rule)
+	}
+	return nil
+}
+
+// validate checks a rule against the model and the schema.
+func (p *Perm) validate(e casbin.IEnforcer, ptype string, rule []string) error {
+	ast, ok := e.GetModel()[ptype][ptype]
+	if !ok {
+		return fmt.Errorf("%w: model has no %q definition", ErrInvalidRule, ptype)
+	}
+	if len(rule) != len(ast.Tokens) {
+		return fmt.Errorf("%w: %s needs %d values, got %d %v", ErrInvalidRule, ptype, len(ast.Tokens), len(rule), rule)
+	}
+	if slices.Contains(rule, "") {
+		return fmt.Errorf("%w: empty value in %v", ErrInvalidRule, rule)
+	}
+
+	if ptype == "g" {
+		if rule[0] == rule[1] {
+			return fmt.Errorf("%w: %q cannot have itself as role", ErrInvalidRule, rule[0])
+		}
+		if !slices.Contains(p.knownRoles(e), rule[1]) {
+			return fmt.Errorf("%w: %q", ErrUnknownRole, rule[1])
+		}
+		return nil
+	}
+
+	if len(p.schema.Roles) > 0 && !slices.Contains(p.schema.Roles, rule[0]) {
+		return fmt.Errorf("%w: %q", ErrUnknownRole, rule[0])
+	}
+	if len(p.schema.Actions) > 0 {
+		act := rule[actionIndex(ast.Tokens)]
+		if !slices.Contains(p.schema.Actions, act) {
+			return fmt.Errorf("%w: %q", ErrUnknownAction, act)
+		}
+	}
+	return nil
+}
+
+// actionIndex returns the index of the "act" field of the policy
+// definition, or the last field.
+func actionIndex(tokens []string) int {
+	if i := slices.Index(tokens, "p_act"); i >= 0 {
+		return i
+	}
+	return len(tokens) - 1
+}
+
+// save persists a change: to the policy file if set, otherwise with the
+// adapter's SavePolicy. Adapters without save support keep the change in
+// memory.
+func (p *Perm) save(e casbin.IEnforcer, ptype string, rule []string, add bool) error {
+	if p.policyPath != "" {
+		f, err := ReadPolicyFile(p.policyPath)
+		if err != nil {
+			return err
+		}
+		line := append([]string{ptype}, rule...)
+		if add {
+			f.Add(line...)
+		} else {
+			f.Remove(line...)
+		}
+		return f.Save()
+	}
+
+	switch e.GetAdapter().(type) {
+	case nil, *textAdapter:
+		return nil
+	}
+	if err := e.SavePolicy(); err != nil && err.Error() != errNotImplemented.Error() {
+		return err
+	}
+	return nil
+}
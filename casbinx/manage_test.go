@@ -0,0 +1,253 @@
+package casbinx
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// TestPerm_PolicyManagement tests validated changes saved to the policy file
+func TestPerm_PolicyManagement(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, rbacModel, `# Permissions
+p, reader, posts:read
+p, editor, posts:write
+
+# Users
+g, alice, editor
+`)
+
+	perm, err := NewFilePerm(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm.SetSchema(Schema{
+		Roles:   []string{"reader", "editor"},
+		Actions: []string{"posts:read", "posts:write", "posts:delete"},
+	})
+
+	if err := perm.AssignRole("bob", "reader"); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	if err := perm.AddPolicy("editor", "posts:delete"); err != nil {
+		t.Fatalf("AddPolicy: %v", err)
+	}
+	if err := perm.RevokeRole("alice", "editor"); err != nil {
+		t.Fatalf("RevokeRole: %v", err)
+	}
+
+	if !perm.Can("bob", "posts:read") || perm.Can("alice", "posts:write") {
+		t.Error("changes should be active")
+	}
+
+	expected := `# Permissions
+p, reader, posts:read
+p, editor, posts:write
+p, editor, posts:delete
+
+# Users
+g, bob, reader
+`
+	data, _ := os.ReadFile(policyPath)
+	if string(data) != expected {
+		t.Errorf("unexpected policy file:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	// Reloading the saved file yields the same decisions
+	if err := perm.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !perm.Can("bob", "posts:read") || perm.Can("alice", "posts:write") {
+		t.Error("saved policy should match the active policy")
+	}
+}
+
+// TestPerm_PolicyValidation tests rejected changes
+func TestPerm_PolicyValidation(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy)).
+		SetSchema(Schema{Actions: []string{"posts:read", "posts:write"}})
+
+	tests := []struct {
+		name     string
+		change   func() error
+		expected error
+	}{
+		{"unknown role", func() error { return perm.AssignRole("carol", "admin") }, ErrUnknownRole},
+		{"unknown action", func() error { return perm.AddPolicy("reader", "posts:publish") }, ErrUnknownAction},
+		{"wrong arity", func() error { return perm.AddPolicy("reader", "posts", "read") }, ErrInvalidRule},
+		{"empty value", func() error { return perm.AddPolicy("", "posts:read") }, ErrInvalidRule},
+		{"duplicate", func() error { return perm.AddPolicy("reader", "posts:read") }, ErrRuleExists},
+		{"missing role", func() error { return perm.RevokeRole("bob", "editor") }, ErrRuleNotFound},
+		{"self role", func() error { return perm.AssignRole("reader", "reader") }, ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	perm.SetSchema(Schema{Roles: []string{"reader", "editor", "admin"}})
+	if err := perm.AssignRole("carol", "admin"); err != nil {
+		t.Errorf("declared role should be assignable: %v", err)
+	}
+	if err := perm.AddPolicy("carol", "posts:read"); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("policy subject should be a declared role, got %v", err)
+	}
+}
+
+// TestPerm_ChangesWithLoader tests that consecutive changes accumulate
+// instead of being reset by the loader
+func TestPerm_ChangesWithLoader(t *testing.T) {
+	perm, err := NewReloadablePerm(func() (casbin.IEnforcer, error) {
+		return NewStringEnforcer(rbacModel, rbacPolicy)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := perm.AddPolicy("reader", "posts:comment"); err != nil {
+		t.Fatal(err)
+	}
+	if err := perm.AddPolicy("editor", "posts:delete"); err != nil {
+		t.Fatal(err)
+	}
+	if !perm.Can("bob", "posts:comment") || !perm.Can("alice", "posts:delete") {
+		t.Error("both changes should be active")
+	}
+}
+
+// TestPerm_ChangesWithoutLoader tests changes on NewPerm: they are saved
+// through the adapter and survive Reload
+func TestPerm_ChangesWithoutLoader(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, rbacModel, rbacPolicy)
+	e, err := NewFileEnforcer(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm := NewPerm(e)
+
+	if err := perm.AssignRole("carol", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	if err := perm.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !perm.Can("carol", "posts:write") {
+		t.Error("change should be saved and survive Reload")
+	}
+
+	data, _ := os.ReadFile(policyPath)
+	if !strings.Contains(string(data), "g, carol, editor") {
+		t.Errorf("expected change in policy file, got:\n%s", data)
+	}
+}
+
+// TestPerm_ChangesInMemory tests changes on read-only adapters
+func TestPerm_ChangesInMemory(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy))
+	if err := perm.AssignRole("carol", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	if !perm.Can("carol", "posts:write") {
+		t.Error("change should be active")
+	}
+}
+
+// TestPerm_KnownRoles tests that subjects of p rules are assignable roles
+// without a schema
+func TestPerm_KnownRoles(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, rbacModel, "p, editor, posts:write\ng, alice, reader\n")
+	perm, err := NewFilePerm(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"editor", "reader"}
+	if got := perm.KnownRoles(); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if err := perm.AssignRole("bob", "editor"); err != nil {
+		t.Fatalf("role without members should be assignable: %v", err)
+	}
+	if !perm.Can("bob", "posts:write") {
+		t.Error("bob should write after AssignRole")
+	}
+
+	perm.SetSchema(Schema{Roles: []string{"reader"}})
+	if err := perm.AssignRole("carol", "editor"); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("expected ErrUnknownRole for undeclared role, got %v", err)
+	}
+}
+
+// TestPerm_ChangesKeepFunctions tests that changes keep matching functions
+// registered on the enforcer (in place) or by the loader
+func TestPerm_ChangesKeepFunctions(t *testing.T) {
+	newFuncEnforcer := func() (casbin.IEnforcer, error) {
+		e, err := NewStringEnforcer(funcModel, "p, alice, posts:*\n")
+		if err != nil {
+			return nil, err
+		}
+		e.AddFunction("myMatch", prefixMatch)
+		return e, nil
+	}
+
+	e, err := newFuncEnforcer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloadable, err := NewReloadablePerm(newFuncEnforcer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		perm *Perm
+	}{
+		{"in place", NewPerm(e)},
+		{"loader", reloadable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.perm.AddPolicy("bob", "users:*"); err != nil {
+				t.Fatal(err)
+			}
+			for _, req := range [][2]string{{"alice", "posts:read"}, {"bob", "users:read"}} {
+				if ok, err := tt.perm.Check(req[0], req[1]); !ok || err != nil {
+					t.Errorf("%v: expected true <nil>, got %v %v", req, ok, err)
+				}
+			}
+		})
+	}
+}
+
+// TestPolicyFile tests comment-preserving edits
+func TestPolicyFile(t *testing.T) {
+	_, path := writeFiles(t, rbacModel, "p, a, x\r\n# keep\ng, u, a\r\n")
+
+	f, err := ReadPolicyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Add("p", "b", "y, z") || f.Add("p", "a", "x") {
+		t.Error("unexpected Add result")
+	}
+	if !f.Remove("g", "u", "a") || f.Remove("g", "u", "a") {
+		t.Error("unexpected Remove result")
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if expected := "p, a, x\np, b, \"y, z\"\n# keep\n"; string(data) != expected {
+		t.Errorf("got %q, expected %q", data, expected)
+	}
+}
@@ -0,0 +1,320 @@
+package casbinx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/handler"
+)
+
+// SubjectFunc resolves the subject (user ID, username) of a request.
+// It returns "" for anonymous requests.
+type SubjectFunc func(r *http.Request) string
+
+// ActionFunc resolves the action that a request requires.
+// It returns "" if the request maps to no action; Middleware denies such
+// requests without consulting the policy.
+type ActionFunc func(r *http.Request) string
+
+// ObjectFunc resolves the object (resource) a request accesses.
+type ObjectFunc func(r *http.Request) string
+
+// DomainFunc resolves the domain (tenant) of a request.
+type DomainFunc func(r *http.Request) string
+
+// MiddlewareConfig configures MiddlewareWithConfig.
+type MiddlewareConfig struct {
+	// Subject resolves the subject (required).
+	Subject SubjectFunc
+
+	// Action resolves the required action (required).
+	Action ActionFunc
+
+	// Object resolves the object for resource models (r = sub, obj, act);
+	// the check uses Perm.CheckOn. Required if Domain is set.
+	Object ObjectFunc
+
+	// Domain resolves the domain for multi-tenant models
+	// (r = sub, dom, obj, act); the check uses Perm.CheckIn.
+	Domain DomainFunc
+
+	// ErrorHandler handles denials. The error is a *handler.Error with
+	// status 401 (anonymous), 403 (denied) or 500 (enforcer error).
+	// Default: handler.WriteError. Recommended: wrapper.Error.
+	ErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+	// UnauthorizedMessage is shown to anonymous users.
+	// Default: "Please log in to continue."
+	UnauthorizedMessage string
+
+	// ForbiddenMessage is shown to denied users.
+	// Default: "You do not have permission to perform this action."
+	ForbiddenMessage string
+}
+
+// Middleware protects routes with perm: the request is passed on only if
+// the subject may perform the action.
+//
+//   - Anonymous requests (subject "") get 401 Unauthorized
+//   - Denied requests get 403 Forbidden, as do requests without an action
+//     (e.g. a method ActionByMethod does not map), so wildcard rules such
+//     as "p, admin, *" cannot grant them
+//   - Enforcer errors (e.g. a broken matcher function) get 500
+//
+// Middleware is for models with r = sub, act. Use MiddlewareOn for
+// resource models and MiddlewareIn for multi-tenant models.
+//
+// Errors go through handler.WriteError (error toast for HTMX requests,
+// error page for full-page loads). Use MiddlewareWithConfig to use the
+// app's wrapper.Error or custom messages.
+//
+// The subject is stored in the request context (see SubjectFromRequest).
+//
+// Example:
+//
+//	user := func(r *http.Request) string {
+//	    return session.Manager.GetString(r.Context(), "user_id")
+//	}
+//
+//	r.With(casbinx.Middleware(perm, user, casbinx.Action("users:manage"))).
+//	    Get("/admin/users", wrapper.Wrap(admin.Users))
+func Middleware(perm *Perm, subject SubjectFunc, action ActionFunc) func(http.Handler) http.Handler {
+	return MiddlewareWithConfig(perm, MiddlewareConfig{Subject: subject, Action: action})
+}
+
+// MiddlewareOn is Middleware for resource models (r = sub, obj, act):
+//
+//	r.With(casbinx.MiddlewareOn(perm, user, casbinx.ObjectFromPathValue("resource"), casbinx.Action("read"))).
+//	    Get("/files/{resource}", wrapper.Wrap(files.Show))
+func MiddlewareOn(perm *Perm, subject SubjectFunc, object ObjectFunc, action ActionFunc) func(http.Handler) http.Handler {
+	return MiddlewareWithConfig(perm, MiddlewareConfig{Subject: subject, Object: object, Action: action})
+}
+
+// MiddlewareIn is Middleware for multi-tenant models (r = sub, dom, obj, act):
+//
+//	r.With(casbinx.MiddlewareIn(perm, user, casbinx.DomainFromPathValue("tenant"),
+//	    casbinx.Object("posts"), casbinx.Action("write"))).
+//	    Post("/{tenant}/posts", wrapper.Wrap(posts.Create))
+func MiddlewareIn(perm *Perm, subject SubjectFunc, domain DomainFunc, object ObjectFunc, action ActionFunc) func(http.Handler) http.Handler {
+	return MiddlewareWithConfig(perm, MiddlewareConfig{Subject: subject, Domain: domain, Object: object, Action: action})
+}
+
+// MiddlewareWithConfig returns the authorization middleware with custom configuration.
+//
+// The configured resolvers select the check: Subject and Action use
+// Perm.Check, plus Object Perm.CheckOn, plus Domain Perm.CheckIn.
+//
+// Panics if Subject or Action is nil - a route without a check is exactly
+// what this middleware prevents - if Domain is set without Object, or if
+// the model's request definition does not match the resolvers (see
+// Perm.RequireShape), so a wrong middleware fails at startup instead of
+// answering every request with 500.
+func MiddlewareWithConfig(perm *Perm, cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	if perm == nil || cfg.Subject == nil || cfg.Action == nil {
+		panic("casbinx: Middleware requires perm, subject and action")
+	}
+	if cfg.Domain != nil && cfg.Object == nil {
+		panic("casbinx: Middleware with a domain requires an object")
+	}
+	shape := ShapeSubAct
+	switch {
+	case cfg.Domain != nil:
+		shape = ShapeSubDomObjAct
+	case cfg.Object != nil:
+		shape = ShapeSubObjAct
+	}
+	if err := perm.RequireShape(shape); err != nil {
+		panic(err.Error())
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = handler.WriteError
+	}
+	if cfg.UnauthorizedMessage == "" {
+		cfg.UnauthorizedMessage = "Please log in to continue."
+	}
+	if cfg.ForbiddenMessage == "" {
+		cfg.ForbiddenMessage = "You do not have permission to perform this action."
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sub := cfg.Subject(r)
+			if sub == "" {
+				cfg.ErrorHandler(w, r, handler.NewError(http.StatusUnauthorized, cfg.UnauthorizedMessage))
+				return
+			}
+
+			denied := &DeniedError{Subject: sub, Action: cfg.Action(r)}
+			if denied.Action == "" {
+				cfg.ErrorHandler(w, r, handler.WrapError(http.StatusForbidden, cfg.ForbiddenMessage, denied))
+				return
+			}
+
+			var ok bool
+			var err error
+			switch shape {
+			case ShapeSubDomObjAct:
+				denied.Domain, denied.Object = cfg.Domain(r), cfg.Object(r)
+				ok, err = perm.CheckIn(denied.Domain, sub, denied.Object, denied.Action)
+			case ShapeSubObjAct:
+				denied.Object = cfg.Object(r)
+				ok, err = perm.CheckOn(sub, denied.Object, denied.Action)
+			default:
+				ok, err = perm.Check(sub, denied.Action)
+			}
+			if err != nil {
+				// A broken model must not look like "access denied"
+				cfg.ErrorHandler(w, r, handler.WrapError(http.StatusInternalServerError, "", err))
+				return
+			}
+			if !ok {
+				cfg.ErrorHandler(w, r, handler.WrapError(http.StatusForbidden, cfg.ForbiddenMessage, denied))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ctxkeys.Subject, sub)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// DeniedError is the underlying error of 403 responses (logged, not shown).
+// Object and Domain are empty for models without them.
+type DeniedError struct {
+	Subject string
+	Action  string
+	Object  string
+	Domain  string
+}
+
+// Error implements the error interface.
+func (e *DeniedError) Error() string {
+	if e.Action == "" {
+		return "casbinx: request of " + e.Subject + " maps to no action"
+	}
+	msg := "casbinx: " + e.Subject + " may not " + e.Action
+	if e.Object != "" {
+		msg += " on " + e.Object
+	}
+	if e.Domain != "" {
+		msg += " in " + e.Domain
+	}
+	return msg
+}
+
+// SubjectFromRequest returns the subject stored by Middleware, or "".
+func SubjectFromRequest(r *http.Request) string {
+	sub, _ := r.Context().Value(ctxkeys.Subject).(string)
+	return sub
+}
+
+// WithSubject stores the subject in the request context without checking
+// a permission, so IfCan and Menu work on public pages too:
+//
+//	r.Use(casbinx.WithSubject(user))
+func WithSubject(subject SubjectFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sub := subject(r); sub != "" {
+				r = r.WithContext(context.WithValue(r.Context(), ctxkeys.Subject, sub))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Subject resolvers
+
+// SubjectFromHeader reads the subject from a request header
+// (e.g. "X-Forwarded-User" set by an authenticating proxy).
+//
+// Only use this behind a proxy that strips the header from client requests.
+func SubjectFromHeader(name string) SubjectFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// SubjectFromContext reads the subject from a string context value
+// (e.g. set by the app's authentication middleware).
+func SubjectFromContext(key any) SubjectFunc {
+	return func(r *http.Request) string {
+		sub, _ := r.Context().Value(key).(string)
+		return sub
+	}
+}
+
+// Object and domain resolvers
+
+// Object returns a fixed object for all requests.
+func Object(obj string) ObjectFunc {
+	return func(*http.Request) string {
+		return obj
+	}
+}
+
+// ObjectFromPathValue reads the object from a route wildcard of
+// http.ServeMux (r.PathValue). For Chi, use chi.URLParam in your own
+// ObjectFunc.
+func ObjectFromPathValue(name string) ObjectFunc {
+	return func(r *http.Request) string {
+		return r.PathValue(name)
+	}
+}
+
+// DomainFromPathValue reads the domain from a route wildcard of
+// http.ServeMux (r.PathValue), e.g. "/{tenant}/posts".
+func DomainFromPathValue(name string) DomainFunc {
+	return func(r *http.Request) string {
+		return r.PathValue(name)
+	}
+}
+
+// Action resolvers
+
+// Action returns a fixed action for all requests.
+func Action(action string) ActionFunc {
+	return func(*http.Request) string {
+		return action
+	}
+}
+
+// ActionByMethod maps the HTTP method to an action, e.g.
+//
+//	casbinx.ActionByMethod(map[string]string{
+//	    http.MethodGet:  "posts:read",
+//	    http.MethodPost: "posts:write",
+//	})
+//
+// HEAD falls back to GET. Unmapped methods resolve to "" (no action), so
+// Middleware denies them with 403 even for wildcard policies.
+func ActionByMethod(actions map[string]string) ActionFunc {
+	return func(r *http.Request) string {
+		if act, ok := actions[r.Method]; ok {
+			return act
+		}
+		if r.Method == http.MethodHead {
+			return actions[http.MethodGet]
+		}
+		return ""
+	}
+}
+
+// ActionFromRoute uses the route pattern as action, e.g. "GET /users/{id}"
+// (http.ServeMux sets r.Pattern). Without a pattern, method and path are
+// used ("GET /users/42").
+//
+// For Chi, pass the pattern yourself:
+//
+//	func(r *http.Request) string {
+//	    return r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+//	}
+func ActionFromRoute() ActionFunc {
+	return func(r *http.Request) string {
+		if r.Pattern != "" {
+			return r.Pattern
+		}
+		return r.Method + " " + r.URL.Path
+	}
+}
@@ -0,0 +1,239 @@
+package casbinx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMiddleware tests 401/403/pass-through and the stored subject
+func TestMiddleware(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy))
+
+	mw := Middleware(perm, SubjectFromHeader("X-User"), ActionByMethod(map[string]string{
+		http.MethodGet:  "posts:read",
+		http.MethodPost: "posts:write",
+	}))
+
+	var gotSubject string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSubject = SubjectFromRequest(r)
+	})
+
+	tests := []struct {
+		name         string
+		method       string
+		user         string
+		htmx         bool
+		expectedCode int
+	}{
+		{"anonymous", http.MethodGet, "", false, http.StatusUnauthorized},
+		{"reader reads", http.MethodGet, "bob", false, http.StatusOK},
+		{"head falls back to get", http.MethodHead, "bob", false, http.StatusOK},
+		{"reader writes", http.MethodPost, "bob", false, http.StatusForbidden},
+		{"reader writes via htmx", http.MethodPost, "bob", true, http.StatusForbidden},
+		{"editor writes", http.MethodPost, "alice", false, http.StatusOK},
+		{"unmapped method", http.MethodDelete, "alice", false, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSubject = ""
+			req := httptest.NewRequest(tt.method, "/posts", nil)
+			if tt.user != "" {
+				req.Header.Set("X-User", tt.user)
+			}
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			mw(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode == http.StatusOK && gotSubject != tt.user {
+				t.Errorf("expected subject %q in context, got %q", tt.user, gotSubject)
+			}
+			if tt.htmx && rec.Header().Get("HX-Trigger") == "" {
+				t.Error("expected error toast via HX-Trigger for htmx request")
+			}
+		})
+	}
+}
+
+// TestMiddleware_NoAction tests that requests without an action are denied
+// without consulting the policy, which a wildcard rule would grant
+func TestMiddleware_NoAction(t *testing.T) {
+	const wildcardModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && keyMatch(r.act, p.act)
+`
+	perm := NewPerm(newEnforcer(t, wildcardModel, "p, admin, *\n"))
+	if !perm.Can("admin", "") {
+		t.Fatal("precondition: the wildcard should match the empty action")
+	}
+
+	var denied *DeniedError
+	mw := MiddlewareWithConfig(perm, MiddlewareConfig{
+		Subject: SubjectFromHeader("X-User"),
+		Action:  ActionByMethod(map[string]string{http.MethodGet: "posts:read"}),
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			errors.As(err, &denied)
+			w.WriteHeader(http.StatusForbidden)
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/posts", nil)
+	req.Header.Set("X-User", "admin")
+	rec := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rec.Code)
+	}
+	if denied == nil || denied.Action != "" {
+		t.Errorf("expected DeniedError without action, got %v", denied)
+	}
+}
+
+// TestActionFromRoute tests pattern and fallback actions
+func TestActionFromRoute(t *testing.T) {
+	var got string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		got = ActionFromRoute()(r)
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+
+	if got != "GET /users/{id}" {
+		t.Errorf("expected route pattern, got %q", got)
+	}
+
+	if got := ActionFromRoute()(httptest.NewRequest("POST", "/x", nil)); got != "POST /x" {
+		t.Errorf("expected method and path, got %q", got)
+	}
+}
+
+// TestMiddleware_ShapeMismatch tests that a middleware not matching the
+// model fails at construction instead of answering with 500
+func TestMiddleware_ShapeMismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		mw    func(perm *Perm) func(http.Handler) http.Handler
+	}{
+		{"sub act on resource model", objModel, func(perm *Perm) func(http.Handler) http.Handler {
+			return Middleware(perm, SubjectFromHeader("X-User"), Action("read"))
+		}},
+		{"resource on domain model", domainModel, func(perm *Perm) func(http.Handler) http.Handler {
+			return MiddlewareOn(perm, SubjectFromHeader("X-User"), Object("posts"), Action("read"))
+		}},
+		{"domain without object", domainModel, func(perm *Perm) func(http.Handler) http.Handler {
+			return MiddlewareWithConfig(perm, MiddlewareConfig{
+				Subject: SubjectFromHeader("X-User"),
+				Domain:  DomainFromPathValue("tenant"),
+				Action:  Action("read"),
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perm := NewPerm(newEnforcer(t, tt.model, ""))
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.mw(perm)
+		})
+	}
+}
+
+// TestMiddlewareOn tests resource checks with the object from the route
+func TestMiddlewareOn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, objModel, "p, alice, report, read\n"))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /files/{name}", MiddlewareOn(perm, SubjectFromHeader("X-User"),
+		ObjectFromPathValue("name"), Action("read"))(http.NotFoundHandler()))
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{"allowed object", "/files/report", http.StatusNotFound},
+		{"other object", "/files/salaries", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("X-User", "alice")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+// TestMiddlewareIn tests domain checks with the tenant from the route
+func TestMiddlewareIn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+
+	var denied error
+	mux := http.NewServeMux()
+	mux.Handle("POST /{tenant}/posts", MiddlewareWithConfig(perm, MiddlewareConfig{
+		Subject: SubjectFromHeader("X-User"),
+		Domain:  DomainFromPathValue("tenant"),
+		Object:  Object("posts"),
+		Action:  Action("write"),
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			denied = err
+			w.WriteHeader(http.StatusForbidden)
+		},
+	})(http.NotFoundHandler()))
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedErr  string
+	}{
+		{"admin tenant", "/tenant1/posts", http.StatusNotFound, ""},
+		{"reader tenant", "/tenant2/posts", http.StatusForbidden, "casbinx: alice may not write on posts in tenant2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			denied = nil
+			req := httptest.NewRequest("POST", tt.path, nil)
+			req.Header.Set("X-User", "alice")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			var de *DeniedError
+			if tt.expectedErr != "" && (!errors.As(denied, &de) || de.Error() != tt.expectedErr) {
+				t.Errorf("expected %q, got %v", tt.expectedErr, denied)
+			}
+		})
+	}
+}
@@ -1,30 +1,192 @@
 package casbinx
 
-import "github.com/casbin/casbin/v2"
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/casbin/casbin/v2"
+)
 
 // Perm kapselt einen Casbin-Enforcer und stellt
 // eine einfache bool-basierte Permission-API bereit.
+//
+// Perm ist nebenläufig nutzbar: Reload mit Loader baut einen neuen
+// Enforcer und tauscht ihn atomar aus, laufende Prüfungen sehen weiter den
+// alten. Ohne Loader wird der Enforcer an Ort und Stelle neu geladen; ein
+// *casbin.Enforcer wird dabei von Perm gesperrt, ein *casbin.SyncedEnforcer
+// sperrt selbst.
 type Perm struct {
-	enforcer *casbin.Enforcer
+	current atomic.Pointer[enforcerRef]
+
+	// mu serialisiert Reload und Änderungen.
+	mu sync.Mutex
+
+	// rw sperrt einen Enforcer ohne eigene Sperre, solange er an Ort und
+	// Stelle geändert wird; Prüfungen lesen unter RLock.
+	rw sync.RWMutex
+
+	// load baut einen neuen Enforcer (nil: Reload lädt den aktuellen neu).
+	load func() (casbin.IEnforcer, error)
+
+	// policyPath ist die Policy-Datei, in die Änderungen geschrieben werden.
+	policyPath string
+
+	// schema begrenzt Rollen und Actions bei Änderungen.
+	schema Schema
+}
+
+// enforcerRef hält den aktuellen Enforcer für atomic.Pointer.
+type enforcerRef struct {
+	e casbin.IEnforcer
+
+	// fields sind die Felder der Request-Definition. Sie werden beim
+	// Speichern gelesen, weil GetModel auch beim SyncedEnforcer nicht
+	// sperrt und LoadPolicy das Modell ersetzt.
+	fields []string
+
+	// guarded: Zugriffe auf e laufen über Perm.rw, weil e an Ort und
+	// Stelle geändert wird und keine eigene Sperre hat.
+	guarded bool
+}
+
+// newEnforcerRef liest die Request-Definition von e.
+func newEnforcerRef(e casbin.IEnforcer) *enforcerRef {
+	return &enforcerRef{e: e, fields: requestFields(e.GetModel())}
 }
 
 // NewPerm erzeugt eine neue Perm-Instanz
 // aus einem bereits initialisierten Enforcer.
-func NewPerm(enforcer *casbin.Enforcer) *Perm {
-	return &Perm{
-		enforcer: enforcer,
+//
+// Akzeptiert *casbin.Enforcer und *casbin.SyncedEnforcer.
+func NewPerm(enforcer casbin.IEnforcer) *Perm {
+	ref := newEnforcerRef(enforcer)
+	ref.guarded = !selfLocking(enforcer)
+
+	p := &Perm{}
+	p.current.Store(ref)
+	return p
+}
+
+// selfLocking meldet, ob e Reload und Änderungen selbst sperrt.
+func selfLocking(e casbin.IEnforcer) bool {
+	switch e.(type) {
+	case *casbin.SyncedEnforcer, *casbin.SyncedCachedEnforcer:
+		return true
 	}
+	return false
 }
 
-// Can prüft, ob sub die angegebene Action darf.
-func (p *Perm) Can(sub, action string) bool {
-	ok, err := p.enforcer.Enforce(sub, action)
-	if err != nil {
-		return false
+// Enforcer liefert den aktuellen Enforcer.
+// Nach einem Reload mit Loader ist das ein anderer als der übergebene.
+//
+// Direkte Aufrufe umgehen die Sperre von Perm: einen *casbin.Enforcer aus
+// NewPerm nicht neben Reload oder Änderungen benutzen.
+func (p *Perm) Enforcer() casbin.IEnforcer {
+	return p.current.Load().e
+}
+
+// read liefert den aktuellen Enforcer für einen lesenden Zugriff und die
+// Funktion, die den Zugriff beendet.
+func (p *Perm) read() (casbin.IEnforcer, func()) {
+	ref := p.current.Load()
+	if !ref.guarded {
+		return ref.e, func() {}
+	}
+	p.rw.RLock()
+	return ref.e, p.rw.RUnlock
+}
+
+// Shape liefert die Request-Form des geladenen Modells (r = ...).
+func (p *Perm) Shape() Shape {
+	return detectShape(p.RequestFields())
+}
+
+// RequestFields liefert die Felder der Request-Definition, z.B. [sub obj act].
+func (p *Perm) RequestFields() []string {
+	return slices.Clone(p.current.Load().fields)
+}
+
+// RequireShape prüft, ob das Modell die erwartete Request-Form hat.
+// Gedacht für den Start der Anwendung, damit ein falsches Modell sofort
+// auffällt statt als "Zugriff verweigert":
+//
+//	if err := perm.RequireShape(casbinx.ShapeSubObjAct); err != nil {
+//	    log.Fatal(err)
+//	}
+func (p *Perm) RequireShape(want Shape) error {
+	return p.checkShape("RequireShape", want)
+}
+
+// checkShape liefert einen *ShapeError, wenn das Modell nicht want entspricht.
+func (p *Perm) checkShape(check string, want Shape) error {
+	return p.current.Load().shapeError(check, want)
+}
+
+// shapeOf liefert einen *ShapeError, wenn das Modell von e nicht want entspricht.
+func shapeOf(e casbin.IEnforcer, check string, want Shape) error {
+	return newEnforcerRef(e).shapeError(check, want)
+}
+
+// shapeError liefert einen *ShapeError, wenn fields nicht want entspricht.
+func (r *enforcerRef) shapeError(check string, want Shape) error {
+	if detectShape(r.fields) != want {
+		return &ShapeError{Check: check, Want: want, Fields: slices.Clone(r.fields)}
 	}
+	return nil
+}
+
+// enforce prüft die Request-Form und ruft den Enforcer auf.
+func (p *Perm) enforce(check string, want Shape, rvals ...any) (bool, error) {
+	ref := p.current.Load()
+	if err := ref.shapeError(check, want); err != nil {
+		return false, err
+	}
+	if ref.guarded {
+		p.rw.RLock()
+		defer p.rw.RUnlock()
+	}
+	return ref.e.Enforce(rvals...)
+}
+
+// Can prüft, ob sub die angegebene Action darf (Modell: r = sub, act).
+// Fehler (falsches Modell, kaputter Matcher) gelten als "verweigert";
+// Check liefert sie zurück.
+func (p *Perm) Can(sub, action string) bool {
+	ok, _ := p.Check(sub, action)
+	return ok
+}
+
+// Check ist Can mit Fehlerrückgabe.
+func (p *Perm) Check(sub, action string) (bool, error) {
+	return p.enforce("Can", ShapeSubAct, sub, action)
+}
+
+// CanOn prüft, ob sub die Action auf dem Objekt obj darf
+// (Modell: r = sub, obj, act).
+func (p *Perm) CanOn(sub, obj, action string) bool {
+	ok, _ := p.CheckOn(sub, obj, action)
 	return ok
 }
 
+// CheckOn ist CanOn mit Fehlerrückgabe.
+func (p *Perm) CheckOn(sub, obj, action string) (bool, error) {
+	return p.enforce("CanOn", ShapeSubObjAct, sub, obj, action)
+}
+
+// CanIn prüft, ob sub die Action auf obj in der Domain (Mandant) darf
+// (Modell: r = sub, dom, obj, act).
+func (p *Perm) CanIn(domain, sub, obj, action string) bool {
+	ok, _ := p.CheckIn(domain, sub, obj, action)
+	return ok
+}
+
+// CheckIn ist CanIn mit Fehlerrückgabe.
+func (p *Perm) CheckIn(domain, sub, obj, action string) (bool, error) {
+	return p.enforce("CanIn", ShapeSubDomObjAct, sub, domain, obj, action)
+}
+
 // CanAny prüft, ob sub mindestens eine der Actions darf.
 func (p *Perm) CanAny(sub string, actions ...string) bool {
 	for _, action := range actions {
@@ -47,42 +209,136 @@ func (p *Perm) CanAll(sub string, actions ...string) bool {
 
 // GetRolesForUser gibt alle Rollen zurück, die einem User zugewiesen sind.
 func (p *Perm) GetRolesForUser(user string) []string {
-	roles, err := p.enforcer.GetRolesForUser(user)
-	if err != nil {
-		return nil
-	}
+	roles, _ := p.RolesForUser(user)
 	return roles
 }
 
+// RolesForUser ist GetRolesForUser mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Rollen und keinen Fehler.
+func (p *Perm) RolesForUser(user string) ([]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetRolesForUser(user)
+}
+
+// GetRolesForUserInDomain gibt alle Rollen zurück, die einem User
+// in einer Domain zugewiesen sind.
+func (p *Perm) GetRolesForUserInDomain(user, domain string) []string {
+	roles, _ := p.RolesForUserInDomain(user, domain)
+	return roles
+}
+
+// RolesForUserInDomain ist GetRolesForUserInDomain mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Rollen und keinen Fehler.
+func (p *Perm) RolesForUserInDomain(user, domain string) ([]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetRolesForUser(user, domain)
+}
+
+// GetUsersForRoleInDomain gibt alle User zurück, die eine Rolle
+// in einer Domain haben.
+func (p *Perm) GetUsersForRoleInDomain(role, domain string) []string {
+	users, _ := p.UsersForRoleInDomain(role, domain)
+	return users
+}
+
+// UsersForRoleInDomain ist GetUsersForRoleInDomain mit Fehlerrückgabe.
+// Eine leere Policy liefert keine User und keinen Fehler.
+func (p *Perm) UsersForRoleInDomain(role, domain string) ([]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetUsersForRole(role, domain)
+}
+
+// GetPermissionsForUserInDomain gibt die Policy-Regeln eines Users in einer
+// Domain zurück, einschließlich der Regeln seiner Rollen.
+func (p *Perm) GetPermissionsForUserInDomain(user, domain string) [][]string {
+	permissions, _ := p.PermissionsForUserInDomain(user, domain)
+	return permissions
+}
+
+// PermissionsForUserInDomain ist GetPermissionsForUserInDomain mit
+// Fehlerrückgabe. Eine leere Policy liefert keine Regeln und keinen Fehler.
+func (p *Perm) PermissionsForUserInDomain(user, domain string) ([][]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetImplicitPermissionsForUser(user, domain)
+}
+
+// GetDomainsForUser gibt alle Domains zurück, in denen ein User Rollen hat.
+func (p *Perm) GetDomainsForUser(user string) []string {
+	domains, _ := p.DomainsForUser(user)
+	return domains
+}
+
+// DomainsForUser ist GetDomainsForUser mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Domains und keinen Fehler.
+func (p *Perm) DomainsForUser(user string) ([]string, error) {
+	e, done := p.read()
+	defer done()
+
+	d, ok := e.(interface {
+		GetDomainsForUser(user string) ([]string, error)
+	})
+	if !ok {
+		return nil, errors.New("casbinx: enforcer does not support GetDomainsForUser")
+	}
+	return d.GetDomainsForUser(user)
+}
+
+// GetAllDomains gibt alle Domains aus der Policy zurück.
+func (p *Perm) GetAllDomains() []string {
+	domains, _ := p.AllDomains()
+	return domains
+}
+
+// AllDomains ist GetAllDomains mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Domains und keinen Fehler.
+func (p *Perm) AllDomains() ([]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetAllDomains()
+}
+
 // GetAllSubjects gibt alle Subjects (User) aus der Policy zurück.
 func (p *Perm) GetAllSubjects() []string {
-	subjects, err := p.enforcer.GetAllSubjects()
-	if err != nil {
-		return nil
-	}
+	subjects, _ := p.AllSubjects()
 	return subjects
 }
 
+// AllSubjects ist GetAllSubjects mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Subjects und keinen Fehler.
+func (p *Perm) AllSubjects() ([]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetAllSubjects()
+}
+
 // GetPolicy gibt alle Policy-Regeln zurück.
 func (p *Perm) GetPolicy() [][]string {
-	policies, err := p.enforcer.GetPolicy()
-	if err != nil {
-		return nil
-	}
+	policies, _ := p.Policy()
 	return policies
 }
 
+// Policy ist GetPolicy mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Regeln und keinen Fehler.
+func (p *Perm) Policy() ([][]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetPolicy()
+}
+
 // GetGroupingPolicy gibt alle Rollen-Zuweisungen zurück.
 func (p *Perm) GetGroupingPolicy() [][]string {
-	grouping, err := p.enforcer.GetGroupingPolicy()
-	if err != nil {
-		return nil
-	}
+	grouping, _ := p.GroupingPolicy()
 	return grouping
 }
 
-// Reload lädt die Policy-Datei neu.
-// Nützlich wenn die policy.csv zur Laufzeit geändert wurde.
-func (p *Perm) Reload() error {
-	return p.enforcer.LoadPolicy()
+// GroupingPolicy ist GetGroupingPolicy mit Fehlerrückgabe.
+// Eine leere Policy liefert keine Zuweisungen und keinen Fehler.
+func (p *Perm) GroupingPolicy() ([][]string, error) {
+	e, done := p.read()
+	defer done()
+	return e.GetGroupingPolicy()
 }
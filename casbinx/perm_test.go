@@ -0,0 +1,225 @@
+package casbinx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// objModel is an RBAC model with resources (sub, obj, act).
+const objModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`
+
+// domainModel is a multi-tenant RBAC model (sub, dom, obj, act).
+const domainModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`
+
+// domainPolicy makes alice admin of tenant1 and reader of tenant2.
+const domainPolicy = `
+p, admin, tenant1, posts, write
+p, reader, tenant2, posts, read
+g, alice, admin, tenant1
+g, alice, reader, tenant2
+`
+
+// TestPerm_Shape tests shape detection and arity checks
+func TestPerm_Shape(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		expected Shape
+	}{
+		{"sub act", rbacModel, ShapeSubAct},
+		{"sub obj act", objModel, ShapeSubObjAct},
+		{"sub dom obj act", domainModel, ShapeSubDomObjAct},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perm := NewPerm(newEnforcer(t, tt.model, ""))
+			if got := perm.Shape(); got != tt.expected {
+				t.Errorf("expected shape %s, got %s", tt.expected, got)
+			}
+			if err := perm.RequireShape(tt.expected); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	perm := NewPerm(newEnforcer(t, rbacModel, ""))
+	err := perm.RequireShape(ShapeSubObjAct)
+	if !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("expected ErrShapeMismatch, got %v", err)
+	}
+	if want := "casbinx: RequireShape needs a model with r = sub, obj, act, but the model defines r = sub, act"; err.Error() != want {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+// TestPerm_CanOn tests object-aware checks
+func TestPerm_CanOn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, objModel, `
+p, editor, posts, write
+g, alice, editor
+`))
+
+	if !perm.CanOn("alice", "posts", "write") {
+		t.Error("alice should write posts")
+	}
+	if perm.CanOn("alice", "users", "write") {
+		t.Error("alice should not write users")
+	}
+	if perm.Can("alice", "write") {
+		t.Error("Can should deny on a three-argument model")
+	}
+}
+
+// TestPerm_CanIn tests domain-aware checks and role queries
+func TestPerm_CanIn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+
+	if !perm.CanIn("tenant1", "alice", "posts", "write") {
+		t.Error("alice should write posts in tenant1")
+	}
+	if perm.CanIn("tenant2", "alice", "posts", "write") {
+		t.Error("alice should not write posts in tenant2")
+	}
+	if perm.CanOn("alice", "posts", "write") {
+		t.Error("CanOn should deny on a domain model")
+	}
+
+	if got := perm.GetRolesForUserInDomain("alice", "tenant2"); !reflect.DeepEqual(got, []string{"reader"}) {
+		t.Errorf("unexpected roles in tenant2: %v", got)
+	}
+	if got := perm.GetUsersForRoleInDomain("admin", "tenant1"); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("unexpected admins in tenant1: %v", got)
+	}
+	if got := perm.GetAllDomains(); len(got) != 2 {
+		t.Errorf("expected 2 domains, got %v", got)
+	}
+}
+
+// TestPerm_ErrorVariants tests that errors are surfaced instead of denied
+func TestPerm_ErrorVariants(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, objModel, ""))
+
+	if _, err := perm.Check("alice", "write"); !errors.Is(err, ErrShapeMismatch) {
+		t.Errorf("expected ErrShapeMismatch from Check, got %v", err)
+	}
+
+	subjects, err := perm.AllSubjects()
+	if err != nil || len(subjects) != 0 {
+		t.Errorf("empty policy should yield no subjects and no error, got %v, %v", subjects, err)
+	}
+}
+
+// TestPerm_DomainErrorVariants tests the error-returning domain queries
+func TestPerm_DomainErrorVariants(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+
+	roles, err := perm.RolesForUserInDomain("alice", "tenant1")
+	if err != nil || !reflect.DeepEqual(roles, []string{"admin"}) {
+		t.Errorf("unexpected roles in tenant1: %v, %v", roles, err)
+	}
+	users, err := perm.UsersForRoleInDomain("reader", "tenant2")
+	if err != nil || !reflect.DeepEqual(users, []string{"alice"}) {
+		t.Errorf("unexpected readers in tenant2: %v, %v", users, err)
+	}
+	permissions, err := perm.PermissionsForUserInDomain("alice", "tenant1")
+	want := [][]string{{"admin", "tenant1", "posts", "write"}}
+	if err != nil || !reflect.DeepEqual(permissions, want) {
+		t.Errorf("unexpected permissions in tenant1: %v, %v", permissions, err)
+	}
+	if got := perm.GetPermissionsForUserInDomain("alice", "tenant1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPermissionsForUserInDomain should match, got %v", got)
+	}
+}
+
+// TestPerm_Explain tests the deciding rule and role chain
+func TestPerm_Explain(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy))
+
+	tests := []struct {
+		name          string
+		rvals         []string
+		expectAllowed bool
+		expectRule    []string
+		expectRoles   []string
+		expectString  string
+	}{
+		{"role chain", []string{"alice", "posts:read"}, true, []string{"reader", "posts:read"}, []string{"alice", "editor", "reader"},
+			"alice posts:read: allowed by [reader posts:read] via alice -> editor -> reader"},
+		{"direct role", []string{"editor", "posts:write"}, true, []string{"editor", "posts:write"}, nil,
+			"editor posts:write: allowed by [editor posts:write]"},
+		{"denied", []string{"bob", "posts:write"}, false, nil, nil,
+			"bob posts:write: denied (no matching rule)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp, err := perm.Explain(tt.rvals...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if exp.Allowed != tt.expectAllowed {
+				t.Errorf("expected allowed=%v", tt.expectAllowed)
+			}
+			if len(exp.Rule) != 0 || len(tt.expectRule) != 0 {
+				if !reflect.DeepEqual(exp.Rule, tt.expectRule) {
+					t.Errorf("expected rule %v, got %v", tt.expectRule, exp.Rule)
+				}
+			}
+			if !reflect.DeepEqual(exp.Roles, tt.expectRoles) {
+				t.Errorf("expected roles %v, got %v", tt.expectRoles, exp.Roles)
+			}
+			if got := exp.String(); got != tt.expectString {
+				t.Errorf("expected %q, got %q", tt.expectString, got)
+			}
+		})
+	}
+
+	if _, err := perm.Explain("alice"); !errors.Is(err, ErrShapeMismatch) {
+		t.Errorf("expected ErrShapeMismatch for wrong arity, got %v", err)
+	}
+}
+
+// TestPerm_ExplainDomain tests role chains within a domain
+func TestPerm_ExplainDomain(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+
+	exp, err := perm.Explain("alice", "tenant1", "posts", "write")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exp.Allowed || !reflect.DeepEqual(exp.Roles, []string{"alice", "admin"}) {
+		t.Errorf("unexpected explanation: %s", exp)
+	}
+}
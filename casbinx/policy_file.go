@@ -0,0 +1,167 @@
+package casbinx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PolicyFile is a Casbin policy CSV file that keeps comments, blank lines
+// and the order of rules when rules are added or removed.
+type PolicyFile struct {
+	path  string
+	lines []policyLine
+}
+
+// policyLine is a line of a policy file. rule is nil for comments and
+// blank lines.
+type policyLine struct {
+	raw  string
+	rule []string
+}
+
+// ReadPolicyFile reads a policy file. A missing file yields an empty policy.
+func ReadPolicyFile(path string) (*PolicyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	f := &PolicyFile{path: path}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return f, nil
+	}
+	for i, raw := range strings.Split(text, "\n") {
+		rule, err := parsePolicyLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("casbinx: %s line %d: %w", path, i+1, err)
+		}
+		f.lines = append(f.lines, policyLine{raw: raw, rule: rule})
+	}
+	return f, nil
+}
+
+// parsePolicyLine parses a CSV rule like Casbin does. Returns nil for
+// comments and blank lines.
+func parsePolicyLine(line string) ([]string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+	r := csv.NewReader(strings.NewReader(line))
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	rule, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range rule {
+		rule[i] = strings.TrimSpace(rule[i])
+	}
+	return rule, nil
+}
+
+// Rules returns all rules in file order, including the ptype
+// (e.g. [p alice posts:read]).
+func (f *PolicyFile) Rules() [][]string {
+	var rules [][]string
+	for _, l := range f.lines {
+		if l.rule != nil {
+			rules = append(rules, slices.Clone(l.rule))
+		}
+	}
+	return rules
+}
+
+// Add adds a rule (including the ptype) after the last rule of the same
+// ptype, or at the end. Returns false if the rule already exists.
+func (f *PolicyFile) Add(rule ...string) bool {
+	pos := len(f.lines)
+	for i, l := range f.lines {
+		if l.rule == nil {
+			continue
+		}
+		if slices.Equal(l.rule, rule) {
+			return false
+		}
+		if l.rule[0] == rule[0] {
+			pos = i + 1
+		}
+	}
+	line := policyLine{raw: formatPolicyLine(rule), rule: slices.Clone(rule)}
+	f.lines = slices.Insert(f.lines, pos, line)
+	return true
+}
+
+// Remove removes a rule (including the ptype). Returns false if the rule
+// does not exist.
+func (f *PolicyFile) Remove(rule ...string) bool {
+	n := len(f.lines)
+	f.lines = slices.DeleteFunc(f.lines, func(l policyLine) bool {
+		return l.rule != nil && slices.Equal(l.rule, rule)
+	})
+	return len(f.lines) != n
+}
+
+// Bytes returns the file content.
+func (f *PolicyFile) Bytes() []byte {
+	var sb strings.Builder
+	for _, l := range f.lines {
+		sb.WriteString(l.raw)
+		sb.WriteByte('\n')
+	}
+	return []byte(sb.String())
+}
+
+// Save writes the file atomically: the content is written to a temporary
+// file in the same directory, synced and renamed over the original, so
+// readers (and a crash) never see a partially written policy. The file
+// mode of an existing file is kept.
+func (f *PolicyFile) Save() error {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(f.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Clean up on failure; after a successful rename this is a no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(f.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// formatPolicyLine formats a rule as CSV ("p, alice, posts:read").
+// Fields containing commas or quotes are quoted.
+func formatPolicyLine(rule []string) string {
+	fields := make([]string, len(rule))
+	for i, v := range rule {
+		if strings.ContainsAny(v, ",\"\n") || strings.TrimSpace(v) != v {
+			v = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+		}
+		fields[i] = v
+	}
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,155 @@
+package casbinx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// PolicyCase is an expected decision, e.g. "alice, posts, write, allow".
+type PolicyCase struct {
+	// Request values matching the request definition of the model,
+	// e.g. [alice posts write].
+	Request []string `json:"request"`
+
+	// Allow is the expected decision.
+	Allow bool `json:"allow"`
+
+	// Line in the cases file (1-based; JSON: index in the array). 0 for
+	// cases defined in code.
+	Line int `json:"-"`
+}
+
+// String formats the case as "alice, posts, write, allow".
+func (c PolicyCase) String() string {
+	decision := "deny"
+	if c.Allow {
+		decision = "allow"
+	}
+	return formatPolicyLine(append(append([]string{}, c.Request...), decision))
+}
+
+// PolicyFailure is a case whose decision differs from the expectation
+// (or could not be evaluated).
+type PolicyFailure struct {
+	Case PolicyCase
+
+	// Explanation of the actual decision (nil if Err is set).
+	Explanation *Explanation
+
+	// Err is set if the request could not be evaluated (e.g. wrong arity).
+	Err error
+}
+
+// String formats the failure, e.g. "line 3: alice, posts, write, deny:
+// alice posts write: allowed by [editor posts write] via alice -> editor".
+func (f PolicyFailure) String() string {
+	prefix := f.Case.String()
+	if f.Case.Line > 0 {
+		prefix = fmt.Sprintf("line %d: %s", f.Case.Line, prefix)
+	}
+	if f.Err != nil {
+		return prefix + ": " + f.Err.Error()
+	}
+	return prefix + ": " + f.Explanation.String()
+}
+
+// ReadPolicyCases reads expected decisions from a file (see
+// ParsePolicyCases).
+func ReadPolicyCases(path string) ([]PolicyCase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cases, err := ParsePolicyCases(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cases, nil
+}
+
+// ParsePolicyCases parses expected decisions in CSV or JSON format.
+//
+// CSV has one case per line: the request values followed by "allow" or
+// "deny". Blank lines and # comments are ignored:
+//
+//	# editors write, readers only read
+//	alice, posts, write, allow
+//	bob, posts, write, deny
+//
+// JSON is an array of objects:
+//
+//	[{"request": ["alice", "posts", "write"], "allow": true}]
+func ParsePolicyCases(r io.Reader) ([]PolicyCase, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var cases []PolicyCase
+		if err := json.Unmarshal(trimmed, &cases); err != nil {
+			return nil, fmt.Errorf("casbinx: policy cases: %w", err)
+		}
+		for i := range cases {
+			if len(cases[i].Request) == 0 {
+				return nil, fmt.Errorf("casbinx: policy case %d: empty request", i+1)
+			}
+			cases[i].Line = i + 1
+		}
+		return cases, nil
+	}
+
+	var cases []PolicyCase
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	for i, raw := range strings.Split(text, "\n") {
+		fields, err := parsePolicyLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("casbinx: policy cases line %d: %w", i+1, err)
+		}
+		if fields == nil {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("casbinx: policy cases line %d: expected request values and allow/deny", i+1)
+		}
+
+		c := PolicyCase{Request: fields[:len(fields)-1], Line: i + 1}
+		switch strings.ToLower(fields[len(fields)-1]) {
+		case "allow":
+			c.Allow = true
+		case "deny":
+		default:
+			return nil, fmt.Errorf("casbinx: policy cases line %d: expected allow or deny, got %q",
+				i+1, fields[len(fields)-1])
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// CheckPolicy evaluates the cases and returns those whose decision differs
+// from the expectation, with an explanation of the actual decision.
+// In Go tests use casbinxtest.TestPolicy, which reports the failures.
+func CheckPolicy(enforcer casbin.IEnforcer, cases []PolicyCase) []PolicyFailure {
+	perm := NewPerm(enforcer)
+
+	var failures []PolicyFailure
+	for _, c := range cases {
+		exp, err := perm.Explain(c.Request...)
+		switch {
+		case err != nil:
+			failures = append(failures, PolicyFailure{Case: c, Err: err})
+		case exp.Allowed != c.Allow:
+			failures = append(failures, PolicyFailure{Case: c, Explanation: exp})
+		}
+	}
+	return failures
+}
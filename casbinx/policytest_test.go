@@ -0,0 +1,93 @@
+package casbinx
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestParsePolicyCases tests the CSV and JSON formats
+func TestParsePolicyCases(t *testing.T) {
+	expected := []PolicyCase{
+		{Request: []string{"alice", "posts:write"}, Allow: true, Line: 2},
+		{Request: []string{"bob", "posts:write"}, Allow: false, Line: 4},
+	}
+
+	csv := "# editors write\nalice, posts:write, allow\n\r\nbob, posts:write, DENY\n"
+	cases, err := ParsePolicyCases(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.EqualFunc(cases, expected, equalCase) {
+		t.Errorf("CSV: expected %v, got %v", expected, cases)
+	}
+
+	json := `[
+		{"request": ["alice", "posts:write"], "allow": true},
+		{"request": ["bob", "posts:write"], "allow": false}
+	]`
+	cases, err = ParsePolicyCases(strings.NewReader(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected[0].Line, expected[1].Line = 1, 2
+	if !slices.EqualFunc(cases, expected, equalCase) {
+		t.Errorf("JSON: expected %v, got %v", expected, cases)
+	}
+}
+
+// TestParsePolicyCases_Errors tests invalid case files
+func TestParsePolicyCases_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing decision", "alice\n"},
+		{"invalid decision", "alice, posts:read, maybe\n"},
+		{"invalid JSON", `[{"request": "alice"}]`},
+		{"empty JSON request", `[{"allow": true}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePolicyCases(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// TestCheckPolicy tests that failing cases are returned with explanations
+func TestCheckPolicy(t *testing.T) {
+	e := newEnforcer(t, rbacModel, rbacPolicy)
+
+	failures := CheckPolicy(e, []PolicyCase{
+		{Request: []string{"alice", "posts:read"}, Allow: true},
+		{Request: []string{"bob", "posts:write"}, Allow: false},
+		{Request: []string{"alice", "posts:write"}, Allow: false, Line: 3},
+		{Request: []string{"bob", "posts:delete"}, Allow: true},
+		{Request: []string{"bob", "posts", "read"}, Allow: true},
+	})
+
+	if len(failures) != 3 {
+		t.Fatalf("expected 3 failures, got %d: %v", len(failures), failures)
+	}
+	expected := []string{
+		"line 3: alice, posts:write, deny: alice posts:write: allowed by [editor posts:write] via alice -> editor",
+		"bob, posts:delete, allow: bob posts:delete: denied (no matching rule)",
+	}
+	for i, want := range expected {
+		if got := failures[i].String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+	if !errors.Is(failures[2].Err, ErrShapeMismatch) {
+		t.Errorf("expected arity error, got %v", failures[2].Err)
+	}
+}
+
+// equalCase compares policy cases.
+func equalCase(a, b PolicyCase) bool {
+	return slices.Equal(a.Request, b.Request) && a.Allow == b.Allow && a.Line == b.Line
+}
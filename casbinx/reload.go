@@ -0,0 +1,231 @@
+package casbinx
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// NewReloadablePerm creates a Perm whose Reload rebuilds the enforcer with
+// load, so changes to the model and custom setup (matching functions,
+// role managers) are picked up as well.
+//
+// Example:
+//
+//	perm, err := casbinx.NewReloadablePerm(func() (casbin.IEnforcer, error) {
+//	    return casbinx.NewFileEnforcer(cfg.ModelPath, cfg.PolicyPath)
+//	})
+func NewReloadablePerm(load func() (casbin.IEnforcer, error)) (*Perm, error) {
+	e, err := load()
+	if err != nil {
+		return nil, err
+	}
+	p := &Perm{load: load}
+	p.current.Store(newEnforcerRef(e))
+	return p, nil
+}
+
+// NewFilePerm creates a reloadable Perm from a model and policy file
+// (see NewFileEnforcer and Watch).
+//
+// Changes made through AddPolicy, AssignRole etc. are saved to policyPath.
+func NewFilePerm(modelPath, policyPath string) (*Perm, error) {
+	p, err := NewReloadablePerm(func() (casbin.IEnforcer, error) {
+		return NewFileEnforcer(modelPath, policyPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.policyPath = policyPath
+	return p, nil
+}
+
+// Reload reloads the policy.
+//
+// With a loader (NewReloadablePerm, NewFilePerm) a new enforcer is built
+// and swapped atomically: checks running concurrently keep using the
+// previous enforcer, there is no window in which the policy is partially
+// loaded.
+//
+// Without a loader (NewPerm) the enforcer is reloaded in place with
+// LoadPolicy, so matching functions, role managers and link conditions
+// registered on it are kept. A *casbin.SyncedEnforcer locks internally; for
+// a plain *casbin.Enforcer, checks through Perm wait until LoadPolicy is
+// done.
+//
+// In both cases the new policy is validated first: it must load and keep
+// the request shape of the current model. On error the current policy
+// stays active and the error is returned.
+func (p *Perm) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ref := p.current.Load()
+	want := detectShape(ref.fields)
+
+	if p.load == nil {
+		if err := validatePolicy(ref.e, want); err != nil {
+			return err
+		}
+		if ref.guarded {
+			p.rw.Lock()
+			defer p.rw.Unlock()
+		}
+		return ref.e.LoadPolicy()
+	}
+
+	next, err := p.load()
+	if err != nil {
+		return err
+	}
+	if err := shapeOf(next, "Reload", want); err != nil {
+		return err
+	}
+
+	p.current.Store(newEnforcerRef(next))
+	return nil
+}
+
+// validatePolicy loads the policy of e's adapter into a copy of its model
+// and checks the request shape, without touching e.
+func validatePolicy(e casbin.IEnforcer, want Shape) error {
+	adapter := e.GetAdapter()
+	if adapter == nil {
+		return errors.New("casbinx: Reload requires an enforcer with adapter or a loader (NewReloadablePerm)")
+	}
+
+	m := e.GetModel().Copy()
+	m.ClearPolicy()
+	if err := adapter.LoadPolicy(m); err != nil {
+		return err
+	}
+	if fields := requestFields(m); detectShape(fields) != want {
+		return &ShapeError{Check: "Reload", Want: want, Fields: fields}
+	}
+	return nil
+}
+
+// WatchConfig configures Watch.
+type WatchConfig struct {
+	// Files to watch, typically the model and policy file (required).
+	Files []string
+
+	// Interval between checks. Default: 2s.
+	Interval time.Duration
+
+	// Logger for reload outcomes. Default: slog.Default().
+	Logger *slog.Logger
+}
+
+// Watch polls the files for changes (modification time, size and SHA-256)
+// and calls Reload when one changed. Failed reloads are logged and keep
+// the previous policy; the next change triggers a new attempt.
+//
+// Watch blocks until ctx is canceled; run it in a goroutine:
+//
+//	perm, err := casbinx.NewFilePerm("model.conf", "policy.csv")
+//	...
+//	go perm.Watch(ctx, casbinx.WatchConfig{
+//	    Files:  []string{"model.conf", "policy.csv"},
+//	    Logger: logger,
+//	})
+//
+// If a file cannot be read at start, the error is logged and returned.
+//
+// Uses stdlib polling only, so it works on every platform and with
+// editors that replace files instead of writing them in place.
+func (p *Perm) Watch(ctx context.Context, cfg WatchConfig) error {
+	if len(cfg.Files) == 0 {
+		return errors.New("casbinx: Watch requires at least one file")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 2 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	states := make([]fileState, len(cfg.Files))
+	for i, f := range cfg.Files {
+		st, err := statFile(f)
+		if err != nil {
+			// Watch usually runs in a goroutine whose error nobody reads
+			cfg.Logger.Error("casbin policy watch not started", "file", f, "error", err)
+			return err
+		}
+		states[i] = st
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		var changed []string
+		for i, f := range cfg.Files {
+			st, err := statFile(f)
+			if err != nil {
+				// Editors may briefly remove the file while saving; retry next tick
+				cfg.Logger.Warn("casbin policy watch failed", "file", f, "error", err)
+				continue
+			}
+			if st != states[i] {
+				states[i] = st
+				changed = append(changed, f)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		if err := p.Reload(); err != nil {
+			cfg.Logger.Error("casbin policy reload failed, keeping previous policy",
+				"files", changed, "error", err)
+			continue
+		}
+		cfg.Logger.Info("casbin policy reloaded", "files", changed)
+	}
+}
+
+// fileState identifies a file version.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// statFile returns the current state of a file. The hash catches changes
+// within the mtime resolution of the file system.
+func statFile(path string) (fileState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileState{}, fmt.Errorf("casbinx: watch %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fileState{}, fmt.Errorf("casbinx: watch %s: %w", path, err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fileState{}, fmt.Errorf("casbinx: watch %s: %w", path, err)
+	}
+
+	st := fileState{modTime: info.ModTime(), size: info.Size()}
+	copy(st.hash[:], h.Sum(nil))
+	return st, nil
+}
@@ -0,0 +1,215 @@
+package casbinx
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+// writeFiles writes model and policy files to a temp directory.
+func writeFiles(t *testing.T, modelText, policy string) (modelPath, policyPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	modelPath = filepath.Join(dir, "model.conf")
+	policyPath = filepath.Join(dir, "policy.csv")
+	if err := os.WriteFile(modelPath, []byte(modelText), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return modelPath, policyPath
+}
+
+// TestPerm_Reload tests swapping, validation and keeping the old policy
+func TestPerm_Reload(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, rbacModel, "p, reader, posts:read\ng, bob, reader\n")
+
+	perm, err := NewFilePerm(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm.Can("bob", "posts:write") {
+		t.Fatal("bob should not write before reload")
+	}
+
+	os.WriteFile(policyPath, []byte("p, reader, posts:write\ng, bob, reader\n"), 0o644)
+	if err := perm.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if !perm.Can("bob", "posts:write") {
+		t.Error("bob should write after reload")
+	}
+
+	// Model of another shape is rejected, the current policy stays active
+	os.WriteFile(modelPath, []byte(objModel), 0o644)
+	if err := perm.Reload(); err == nil {
+		t.Error("expected error for model with another shape")
+	}
+	if !perm.Can("bob", "posts:write") {
+		t.Error("previous policy should stay active after failed reload")
+	}
+}
+
+// TestPerm_ReloadWithoutLoader tests reloading in place concurrently with
+// checks, for a SyncedEnforcer and a plain Enforcer locked by Perm
+func TestPerm_ReloadWithoutLoader(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func(model.Model) (casbin.IEnforcer, error)
+	}{
+		{"synced", func(m model.Model) (casbin.IEnforcer, error) {
+			return casbin.NewSyncedEnforcer(m, &textAdapter{text: rbacPolicy})
+		}},
+		{"plain", func(m model.Model) (casbin.IEnforcer, error) {
+			return casbin.NewEnforcer(m, &textAdapter{text: rbacPolicy})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := model.NewModelFromString(rbacModel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := tt.new(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			perm := NewPerm(e)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						if !perm.Can("alice", "posts:read") || len(perm.GetRolesForUser("alice")) == 0 {
+							t.Error("alice should read during reload")
+							return
+						}
+					}
+				}()
+			}
+			for i := 0; i < 10; i++ {
+				if err := perm.Reload(); err != nil {
+					t.Fatalf("reload failed: %v", err)
+				}
+			}
+			wg.Wait()
+		})
+	}
+}
+
+// funcModel uses the custom matching function myMatch (see prefixMatch).
+const funcModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && myMatch(r.act, p.act)
+`
+
+// prefixMatch matches "posts:read" against "posts:*".
+func prefixMatch(args ...any) (any, error) {
+	return strings.HasPrefix(args[0].(string), strings.TrimSuffix(args[1].(string), "*")), nil
+}
+
+// TestPerm_ReloadKeepsFunctions tests that an in-place reload keeps
+// matching functions registered on the enforcer
+func TestPerm_ReloadKeepsFunctions(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, funcModel, "p, alice, posts:*\n")
+	e, err := NewFileEnforcer(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.AddFunction("myMatch", prefixMatch)
+	perm := NewPerm(e)
+
+	if ok, err := perm.Check("alice", "posts:read"); !ok || err != nil {
+		t.Fatalf("before reload: expected true <nil>, got %v %v", ok, err)
+	}
+	os.WriteFile(policyPath, []byte("p, alice, posts:*\np, bob, users:*\n"), 0o644)
+	if err := perm.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := perm.Check("bob", "users:read"); !ok || err != nil {
+		t.Errorf("after reload: expected true <nil>, got %v %v", ok, err)
+	}
+
+	// An invalid policy is rejected before the enforcer is touched
+	os.WriteFile(policyPath, []byte("p, alice\n"), 0o644)
+	if err := perm.Reload(); err == nil {
+		t.Error("expected error for invalid policy")
+	}
+	if !perm.Can("bob", "users:read") {
+		t.Error("previous policy should stay active after failed reload")
+	}
+}
+
+// TestPerm_ReloadWithoutAdapter tests that Reload fails instead of panicking
+func TestPerm_ReloadWithoutAdapter(t *testing.T) {
+	m, err := model.NewModelFromString(rbacModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewPerm(e).Reload(); err == nil {
+		t.Error("expected error without adapter and loader")
+	}
+}
+
+// TestPerm_Watch tests reloading on file changes
+func TestPerm_Watch(t *testing.T) {
+	modelPath, policyPath := writeFiles(t, rbacModel, "p, reader, posts:read\n")
+
+	perm, err := NewFilePerm(modelPath, policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- perm.Watch(ctx, WatchConfig{
+			Files:    []string{modelPath, policyPath},
+			Interval: 10 * time.Millisecond,
+			Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+	}()
+
+	// Let Watch record the initial state first
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(policyPath, []byte("p, reader, posts:read\ng, bob, reader\n"), 0o644)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !perm.Can("bob", "posts:read") {
+		if time.Now().After(deadline) {
+			t.Fatal("policy change was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
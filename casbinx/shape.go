@@ -0,0 +1,86 @@
+package casbinx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// Shape is the request shape ("r = ...") of a Casbin model, i.e. which
+// arguments a permission check needs.
+type Shape int
+
+// Request shapes supported by Perm.
+const (
+	ShapeUnknown      Shape = iota // Any other request definition
+	ShapeSubAct                    // r = sub, act               -> Perm.Can
+	ShapeSubObjAct                 // r = sub, obj, act          -> Perm.CanOn
+	ShapeSubDomObjAct              // r = sub, dom, obj, act     -> Perm.CanIn
+)
+
+// String returns the request definition of the shape.
+func (s Shape) String() string {
+	switch s {
+	case ShapeSubAct:
+		return "sub, act"
+	case ShapeSubObjAct:
+		return "sub, obj, act"
+	case ShapeSubDomObjAct:
+		return "sub, dom, obj, act"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrShapeMismatch is returned (wrapped in *ShapeError) when a check does
+// not match the request definition of the model.
+var ErrShapeMismatch = errors.New("casbinx: request shape mismatch")
+
+// ShapeError describes a check that does not fit the model.
+type ShapeError struct {
+	Check  string   // Called check, e.g. "CanOn"
+	Want   Shape    // Shape the check needs
+	Fields []string // Request fields of the model, e.g. [sub act]
+}
+
+// Error implements the error interface.
+func (e *ShapeError) Error() string {
+	return fmt.Sprintf("casbinx: %s needs a model with r = %s, but the model defines r = %s",
+		e.Check, e.Want, strings.Join(e.Fields, ", "))
+}
+
+// Unwrap returns ErrShapeMismatch.
+func (e *ShapeError) Unwrap() error {
+	return ErrShapeMismatch
+}
+
+// requestFields returns the request fields of m without the "r_" prefix.
+func requestFields(m model.Model) []string {
+	ast, ok := m["r"]["r"]
+	if !ok {
+		return nil
+	}
+	fields := make([]string, len(ast.Tokens))
+	for i, t := range ast.Tokens {
+		fields[i] = strings.TrimPrefix(t, "r_")
+	}
+	return fields
+}
+
+// detectShape derives the shape from the request fields.
+// Three- and four-field definitions are matched by position, so models
+// naming their fields differently (e.g. r = user, resource, action) work.
+func detectShape(fields []string) Shape {
+	switch len(fields) {
+	case 2:
+		return ShapeSubAct
+	case 3:
+		return ShapeSubObjAct
+	case 4:
+		return ShapeSubDomObjAct
+	default:
+		return ShapeUnknown
+	}
+}
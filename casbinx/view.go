@@ -0,0 +1,146 @@
+package casbinx
+
+import (
+	"net/http"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// If renders nodes only if sub may perform action; otherwise nothing.
+// Use it to hide buttons and links the user cannot use:
+//
+//	casbinx.If(perm, user, "posts:write",
+//	    h.Button(hx.Post(req, "/posts"), g.Text("New post")),
+//	)
+//
+// Hiding is cosmetic - the route must still be protected (Middleware).
+func If(perm *Perm, sub, action string, nodes ...g.Node) g.Node {
+	return ifAllowed(sub != "" && perm.Can(sub, action), nodes)
+}
+
+// IfOn is If for r = sub, obj, act models.
+func IfOn(perm *Perm, sub, obj, action string, nodes ...g.Node) g.Node {
+	return ifAllowed(sub != "" && perm.CanOn(sub, obj, action), nodes)
+}
+
+// IfIn is If for r = sub, dom, obj, act models.
+func IfIn(perm *Perm, domain, sub, obj, action string, nodes ...g.Node) g.Node {
+	return ifAllowed(sub != "" && perm.CanIn(domain, sub, obj, action), nodes)
+}
+
+// IfCan is If with the subject of the request (see SubjectFromRequest).
+// Anonymous requests see nothing.
+//
+//	casbinx.IfCan(req, perm, "posts:delete", deleteButton(post))
+func IfCan(req *http.Request, perm *Perm, action string, nodes ...g.Node) g.Node {
+	return If(perm, SubjectFromRequest(req), action, nodes...)
+}
+
+// IfCanOn is IfOn with the subject of the request.
+func IfCanOn(req *http.Request, perm *Perm, obj, action string, nodes ...g.Node) g.Node {
+	return IfOn(perm, SubjectFromRequest(req), obj, action, nodes...)
+}
+
+// IfCanIn is IfIn with the subject of the request.
+//
+//	casbinx.IfCanIn(req, perm, tenant, "posts", "write", newPostButton)
+func IfCanIn(req *http.Request, perm *Perm, domain, obj, action string, nodes ...g.Node) g.Node {
+	return IfIn(perm, domain, SubjectFromRequest(req), obj, action, nodes...)
+}
+
+// ifAllowed returns nodes as group, or an empty group.
+func ifAllowed(ok bool, nodes []g.Node) g.Node {
+	if !ok {
+		return g.Group(nil)
+	}
+	return g.Group(nodes)
+}
+
+// MenuItem is an entry of Menu.
+type MenuItem struct {
+	// Label of the link.
+	Label string
+
+	// Path is the app-relative link target (basePath-aware).
+	Path string
+
+	// Action required to see the item. Empty: always visible.
+	Action string
+
+	// Object for r = sub, obj, act models (checked with CanOn).
+	Object string
+
+	// Domain for r = sub, dom, obj, act models (checked with CanIn
+	// together with Object).
+	Domain string
+
+	// Exact marks the item active only on Path itself, not below it.
+	Exact bool
+}
+
+// VisibleItems returns the items the subject of the request may see,
+// for menus with custom markup.
+func VisibleItems(req *http.Request, perm *Perm, items []MenuItem) []MenuItem {
+	sub := SubjectFromRequest(req)
+
+	var visible []MenuItem
+	for _, item := range items {
+		switch {
+		case item.Action == "":
+		case sub == "":
+			continue
+		case item.Domain != "" && !perm.CanIn(item.Domain, sub, item.Object, item.Action):
+			continue
+		case item.Domain == "" && item.Object != "" && !perm.CanOn(sub, item.Object, item.Action):
+			continue
+		case item.Domain == "" && item.Object == "" && !perm.Can(sub, item.Action):
+			continue
+		}
+		visible = append(visible, item)
+	}
+	return visible
+}
+
+// IsActive reports whether the item's page is being viewed
+// (see view.IsActivePrefix).
+func (item MenuItem) IsActive(req *http.Request) bool {
+	if item.Exact || item.Path == "/" {
+		return view.IsActive(req, item.Path)
+	}
+	return view.IsActivePrefix(req, item.Path)
+}
+
+// Menu renders the items the subject of the request may see as a list of
+// links; the active item gets aria-current and view.ActiveClass:
+//
+//	h.Nav(casbinx.Menu(req, perm,
+//	    casbinx.MenuItem{Label: "Home", Path: "/"},
+//	    casbinx.MenuItem{Label: "Posts", Path: "/posts", Action: "posts:read"},
+//	    casbinx.MenuItem{Label: "Users", Path: "/admin/users", Action: "users:manage"},
+//	))
+//
+//	<ul>
+//	  <li><a href="/app/">Home</a></li>
+//	  <li><a href="/app/posts" aria-current="page" class="active">Posts</a></li>
+//	</ul>
+//
+// Nothing is rendered if no item is visible.
+func Menu(req *http.Request, perm *Perm, items ...MenuItem) g.Node {
+	visible := VisibleItems(req, perm, items)
+	if len(visible) == 0 {
+		return nil
+	}
+
+	lis := make([]g.Node, len(visible))
+	for i, item := range visible {
+		lis[i] = h.Li(h.A(
+			h.Href(view.URLString(req, item.Path)),
+			view.ActiveAttrs(item.IsActive(req)),
+			g.Text(item.Label),
+		))
+	}
+	return h.Ul(lis...)
+}
@@ -0,0 +1,154 @@
+package casbinx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+)
+
+// render renders a node to a string ("" for nil).
+func render(t *testing.T, n g.Node) string {
+	t.Helper()
+	if n == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := n.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// requestAs returns a request to path with sub stored as subject.
+func requestAs(path, sub string) *http.Request {
+	var got *http.Request
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User", sub)
+	WithSubject(SubjectFromHeader("X-User"))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+// TestIf tests conditional rendering by permission
+func TestIf(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy))
+	button := g.Text("<edit>")
+
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{"allowed", If(perm, "alice", "posts:write", button), "&lt;edit&gt;"},
+		{"denied", If(perm, "bob", "posts:write", button), ""},
+		{"anonymous", If(perm, "", "posts:write", button), ""},
+		{"request allowed", IfCan(requestAs("/", "bob"), perm, "posts:read", button), "&lt;edit&gt;"},
+		{"request denied", IfCan(requestAs("/", "bob"), perm, "posts:write", button), ""},
+		{"request anonymous", IfCan(requestAs("/", ""), perm, "posts:read", button), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.node == nil {
+				t.Fatal("expected non-nil node")
+			}
+			if got := render(t, tt.node); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestIfOn tests conditional rendering for resource models
+func TestIfOn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, objModel, "p, alice, posts, write\n"))
+
+	if got := render(t, IfOn(perm, "alice", "posts", "write", g.Text("ok"))); got != "ok" {
+		t.Errorf("expected %q, got %q", "ok", got)
+	}
+	if got := render(t, IfOn(perm, "alice", "users", "write", g.Text("ok"))); got != "" {
+		t.Errorf("expected nothing, got %q", got)
+	}
+}
+
+// TestIfCanIn tests conditional rendering for multi-tenant models
+func TestIfCanIn(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+	req := requestAs("/", "alice")
+
+	if got := render(t, IfCanIn(req, perm, "tenant1", "posts", "write", g.Text("ok"))); got != "ok" {
+		t.Errorf("expected %q, got %q", "ok", got)
+	}
+	if got := render(t, IfCanIn(req, perm, "tenant2", "posts", "write", g.Text("ok"))); got != "" {
+		t.Errorf("expected nothing, got %q", got)
+	}
+}
+
+// TestMenu_Domain tests domain-scoped menu items
+func TestMenu_Domain(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, domainModel, domainPolicy))
+	items := []MenuItem{
+		{Label: "Tenant 1", Path: "/tenant1/posts", Action: "write", Object: "posts", Domain: "tenant1"},
+		{Label: "Tenant 2", Path: "/tenant2/posts", Action: "write", Object: "posts", Domain: "tenant2"},
+		{Label: "Read 2", Path: "/tenant2/read", Action: "read", Object: "posts", Domain: "tenant2"},
+	}
+
+	visible := VisibleItems(requestAs("/", "alice"), perm, items)
+	var labels []string
+	for _, item := range visible {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, ",") != "Tenant 1,Read 2" {
+		t.Errorf("unexpected visible items %v", labels)
+	}
+}
+
+// TestMenu tests filtering and active marking
+func TestMenu(t *testing.T) {
+	perm := NewPerm(newEnforcer(t, rbacModel, rbacPolicy))
+	items := []MenuItem{
+		{Label: "Home", Path: "/"},
+		{Label: "Posts", Path: "/posts", Action: "posts:read"},
+		{Label: "New post", Path: "/posts/new", Action: "posts:write", Exact: true},
+	}
+
+	tests := []struct {
+		name     string
+		sub      string
+		path     string
+		expected string
+	}{
+		{
+			"anonymous", "", "/",
+			`<ul><li><a href="/" aria-current="page" class="active">Home</a></li></ul>`,
+		},
+		{
+			"reader", "bob", "/posts/42",
+			`<ul><li><a href="/">Home</a></li><li><a href="/posts" aria-current="page" class="active">Posts</a></li></ul>`,
+		},
+		{
+			"editor", "alice", "/posts/new",
+			`<ul><li><a href="/">Home</a></li>` +
+				`<li><a href="/posts" aria-current="page" class="active">Posts</a></li>` +
+				`<li><a href="/posts/new" aria-current="page" class="active">New post</a></li></ul>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := render(t, Menu(requestAs(tt.path, tt.sub), perm, items...))
+			if got != tt.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+
+	if n := Menu(requestAs("/", ""), perm, items[1:]...); n != nil {
+		t.Error("expected nil menu without visible items")
+	}
+}
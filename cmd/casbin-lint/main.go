@@ -0,0 +1,80 @@
+// Command casbin-lint checks a Casbin policy for problems that Casbin
+// accepts silently (see casbinx.Lint): roles without permissions, role
+// cycles, duplicate and shadowed rules, direct grants to subjects without
+// roles and undeclared actions.
+//
+// Usage:
+//
+//	casbin-lint -model model.conf -policy policy.csv [-actions posts:read,posts:write] [-roles admin,editor]
+//
+// Problems are printed as "policy.csv:LINE: KIND: MESSAGE". The exit code
+// is 0 without problems, 1 with problems and 2 if the model or policy
+// cannot be loaded, so it can gate deployments of policy changes:
+//
+//	go run github.com/axelrhd/hagg-lib/cmd/casbin-lint -model authz/model.conf -policy authz/policy.csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("casbin-lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	modelPath := fs.String("model", "", "model file (required)")
+	policyPath := fs.String("policy", "", "policy CSV file (required)")
+	actions := fs.String("actions", "", "comma-separated list of declared actions")
+	roles := fs.String("roles", "", "comma-separated list of declared roles")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *modelPath == "" || *policyPath == "" {
+		fmt.Fprintln(stderr, "casbin-lint: -model and -policy are required")
+		fs.Usage()
+		return 2
+	}
+
+	// Loading through Casbin reports syntax and arity errors first
+	e, err := casbinx.NewFileEnforcer(*modelPath, *policyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "casbin-lint: %v\n", err)
+		return 2
+	}
+	f, err := casbinx.ReadPolicyFile(*policyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "casbin-lint: %v\n", err)
+		return 2
+	}
+
+	schema := casbinx.Schema{Roles: splitList(*roles), Actions: splitList(*actions)}
+	problems := casbinx.Lint(e.GetModel(), f, schema)
+	for _, p := range problems {
+		fmt.Fprintf(stdout, "%s:%d: %s: %s\n", *policyPath, p.Line, p.Kind, p.Message)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// splitList splits a comma-separated list, ignoring empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.act == p.act
+`
+
+// writeFiles writes model and policy to a temporary directory.
+func writeFiles(t *testing.T, policy string) (modelPath, policyPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	modelPath = filepath.Join(dir, "model.conf")
+	policyPath = filepath.Join(dir, "policy.csv")
+	if err := os.WriteFile(modelPath, []byte(testModel), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return modelPath, policyPath
+}
+
+// TestRun tests output and exit codes
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		extraArgs    []string
+		expectedCode int
+		expectedOut  string
+	}{
+		{
+			"clean", "p, reader, posts:read\ng, bob, reader\n",
+			[]string{"-actions", "posts:read, posts:write"}, 0, "",
+		},
+		{
+			"problems", "p, reader, posts:read\np, reader, posts:raed\ng, bob, reader\n",
+			[]string{"-actions", "posts:read"}, 1,
+			"policy.csv:2: unknown-action: action \"posts:raed\" is not declared\n",
+		},
+		{
+			"invalid policy", "p, reader\n", nil, 2, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modelPath, policyPath := writeFiles(t, tt.policy)
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-model", modelPath, "-policy", policyPath}, tt.extraArgs...)
+
+			code := run(args, &stdout, &stderr)
+			if code != tt.expectedCode {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr.String())
+			}
+			want := ""
+			if tt.expectedOut != "" {
+				want = filepath.Dir(policyPath) + string(filepath.Separator) + tt.expectedOut
+			}
+			if stdout.String() != want {
+				t.Errorf("expected output %q, got %q", want, stdout.String())
+			}
+		})
+	}
+}
+
+// TestRun_Usage tests that missing flags are rejected
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+}
@@ -0,0 +1,83 @@
+// Command casbin-policytest checks a Casbin policy against files of
+// expected decisions (see casbinx.ParsePolicyCases), so policy changes are
+// reviewed against an executable spec:
+//
+//	# policy_test.csv
+//	alice, posts:write, allow
+//	bob, posts:write, deny
+//
+// Usage:
+//
+//	casbin-policytest -model model.conf -policy policy.csv policy_test.csv [more cases...]
+//
+// Failing cases are printed with the deciding rule. The exit code is 0 if
+// all cases pass, 1 if a case fails and 2 if the model, policy or a cases
+// file cannot be loaded.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/axelrhd/hagg-lib/casbinx"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("casbin-policytest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: casbin-policytest -model model.conf -policy policy.csv cases-file...")
+		fs.PrintDefaults()
+	}
+	modelPath := fs.String("model", "", "model file (required)")
+	policyPath := fs.String("policy", "", "policy CSV file (required)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *modelPath == "" || *policyPath == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	e, err := casbinx.NewFileEnforcer(*modelPath, *policyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "casbin-policytest: %v\n", err)
+		return 2
+	}
+
+	var total, failed int
+	for _, path := range fs.Args() {
+		cases, err := casbinx.ReadPolicyCases(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "casbin-policytest: %v\n", err)
+			return 2
+		}
+		failures := casbinx.CheckPolicy(e, cases)
+		for _, f := range failures {
+			fmt.Fprintf(stdout, "%s:%s\n", path, failureLine(f))
+		}
+		total += len(cases)
+		failed += len(failures)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(stdout, "FAIL: %d of %d cases failed\n", failed, total)
+		return 1
+	}
+	fmt.Fprintf(stdout, "ok: %d cases passed\n", total)
+	return 0
+}
+
+// failureLine formats a failure as "LINE: case: explanation".
+func failureLine(f casbinx.PolicyFailure) string {
+	line := f.Case.Line
+	f.Case.Line = 0
+	return fmt.Sprintf("%d: %s", line, f.String())
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testModel = `
+[request_definition]
+r = sub, act
+
+[policy_definition]
+p = sub, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.act == p.act
+`
+
+const testPolicy = `p, reader, posts:read
+p, editor, posts:write
+g, alice, editor
+g, bob, reader
+`
+
+// writeFile writes content to name in dir.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestRun tests output and exit codes
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		cases        string
+		expectedCode int
+		expectedOut  string
+	}{
+		{
+			"pass", "alice, posts:write, allow\nbob, posts:write, deny\n", 0,
+			"ok: 2 cases passed\n",
+		},
+		{
+			"fail", "alice, posts:write, allow\nbob, posts:write, allow\n", 1,
+			"cases.csv:2: bob, posts:write, allow: bob posts:write: denied (no matching rule)\n" +
+				"FAIL: 1 of 2 cases failed\n",
+		},
+		{
+			"invalid cases", "bob, posts:write, maybe\n", 2, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			modelPath := writeFile(t, dir, "model.conf", testModel)
+			policyPath := writeFile(t, dir, "policy.csv", testPolicy)
+			casesPath := writeFile(t, dir, "cases.csv", tt.cases)
+
+			// Relative cases path for stable output
+			t.Chdir(dir)
+
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"-model", modelPath, "-policy", policyPath, filepath.Base(casesPath)}, &stdout, &stderr)
+			if code != tt.expectedCode {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tt.expectedCode, code, stderr.String())
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("expected output %q, got %q", tt.expectedOut, stdout.String())
+			}
+		})
+	}
+}
+
+// TestRun_Usage tests that missing arguments are rejected
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-model", "model.conf", "-policy", "policy.csv"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+}
@@ -0,0 +1,289 @@
+// Package csrf provides CSRF protection for HTMX + gomponents applications.
+//
+// The middleware implements the double-submit cookie pattern using only the
+// standard library:
+//
+//   - A random 32-byte token is stored in an HttpOnly cookie
+//   - Pages embed a masked copy of the token (fresh mask per request, so the
+//     value in the HTML never repeats - mitigates BREACH)
+//   - Unsafe requests (POST, PUT, PATCH, DELETE, ...) must send the masked
+//     token in the X-CSRF-Token header or the csrf_token form field
+//
+// # Setup
+//
+//	wrapper := handler.NewWrapper(logger)
+//	r.Use(csrf.Protect(csrf.Config{ErrorHandler: wrapper.Error}))
+//
+// # Templates
+//
+// Every htmx request carries the token when hx-headers is set on <body>:
+//
+//	h.Body(csrf.HXHeaders(ctx.Req), ...)
+//
+// Plain forms use a hidden input:
+//
+//	h.Form(h.Method("post"), csrf.Input(ctx.Req), ...)
+//
+// In handlers the token is available as ctx.CSRFToken().
+//
+// # Failures
+//
+// Failures are reported as *handler.Error (403) through Config.ErrorHandler.
+// With wrapper.Error, HTMX requests get an error toast and full-page loads
+// get the app's error page.
+//
+// # Dependencies
+//
+// Requires: stdlib (crypto/rand, crypto/subtle, net/http), gomponents,
+// ctxkeys and handler packages
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/handler"
+)
+
+// Default names used when Config fields are empty.
+const (
+	DefaultCookieName = "_csrf"
+	DefaultHeaderName = "X-CSRF-Token"
+	DefaultFieldName  = "csrf_token"
+)
+
+// tokenLength is the length of the raw (unmasked) token in bytes.
+const tokenLength = 32
+
+// Validation errors (wrapped in *handler.Error).
+var (
+	ErrMissingCookie = errors.New("csrf: missing cookie")
+	ErrMissingToken  = errors.New("csrf: missing token")
+	ErrInvalidToken  = errors.New("csrf: invalid token")
+)
+
+// Config configures the CSRF middleware. The zero value is usable.
+type Config struct {
+	CookieName string // Default: "_csrf"
+	HeaderName string // Default: "X-CSRF-Token"
+	FieldName  string // Form field name. Default: "csrf_token"
+
+	// CookiePath defaults to "/".
+	CookiePath string
+
+	// Secure marks the cookie as Secure. Enable in production (HTTPS).
+	Secure bool
+
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// Skip exempts requests from validation (e.g. webhook endpoints).
+	// The token is still issued.
+	Skip func(*http.Request) bool
+
+	// ErrorHandler handles validation failures. The error is a *handler.Error
+	// with status 403 wrapping ErrMissingCookie, ErrMissingToken or ErrInvalidToken.
+	// Default: handler.WriteError. Recommended: wrapper.Error.
+	ErrorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// configKey stores the active Config for the template helpers.
+type configKey struct{}
+
+// requestConfig returns the Config of the middleware handling req.
+func requestConfig(req *http.Request) Config {
+	cfg, ok := req.Context().Value(configKey{}).(Config)
+	if !ok {
+		return Config{}.withDefaults()
+	}
+	return cfg
+}
+
+// withDefaults fills empty fields.
+func (cfg Config) withDefaults() Config {
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultCookieName
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = DefaultHeaderName
+	}
+	if cfg.FieldName == "" {
+		cfg.FieldName = DefaultFieldName
+	}
+	if cfg.CookiePath == "" {
+		cfg.CookiePath = "/"
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = handler.WriteError
+	}
+	return cfg
+}
+
+// Protect returns the CSRF middleware.
+//
+// Safe methods (GET, HEAD, OPTIONS, TRACE) pass through and receive a token
+// cookie if they do not have one yet. All other methods are validated.
+func Protect(cfg Config) func(http.Handler) http.Handler {
+	cfg = cfg.withDefaults()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Reuse the cookie token; issue a new one if missing or malformed
+			token := cookieToken(r, cfg.CookieName)
+			hadCookie := token != nil
+			if !hadCookie {
+				token = newToken()
+				http.SetCookie(w, &http.Cookie{
+					Name:     cfg.CookieName,
+					Value:    base64.RawURLEncoding.EncodeToString(token),
+					Path:     cfg.CookiePath,
+					HttpOnly: true,
+					Secure:   cfg.Secure,
+					SameSite: cfg.SameSite,
+				})
+			}
+
+			// Cached pages must not leak tokens between users
+			w.Header().Add("Vary", "Cookie")
+
+			ctx := context.WithValue(r.Context(), ctxkeys.CSRFToken, mask(token))
+			ctx = context.WithValue(ctx, configKey{}, cfg)
+			r = r.WithContext(ctx)
+
+			if isSafeMethod(r.Method) || (cfg.Skip != nil && cfg.Skip(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := validate(r, cfg, token, hadCookie); err != nil {
+				cfg.ErrorHandler(w, r, handler.WrapError(http.StatusForbidden,
+					"Your session has expired or the request is invalid. Please reload the page and try again.",
+					err,
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validate checks the submitted token against the cookie token.
+func validate(r *http.Request, cfg Config, token []byte, hadCookie bool) error {
+	if !hadCookie {
+		return ErrMissingCookie
+	}
+
+	submitted := r.Header.Get(cfg.HeaderName)
+	if submitted == "" {
+		submitted = r.PostFormValue(cfg.FieldName)
+	}
+	if submitted == "" {
+		return ErrMissingToken
+	}
+
+	if !tokensEqual(token, unmask(submitted)) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// Token returns the masked CSRF token for the request.
+// Returns an empty string if the middleware is not used.
+func Token(req *http.Request) string {
+	token, _ := req.Context().Value(ctxkeys.CSRFToken).(string)
+	return token
+}
+
+// Input renders a hidden form input with the token.
+//
+// Example output:
+//
+//	<input type="hidden" name="csrf_token" value="...">
+func Input(req *http.Request) g.Node {
+	return h.Input(h.Type("hidden"), h.Name(requestConfig(req).FieldName), h.Value(Token(req)))
+}
+
+// Meta renders a meta tag with the token for scripts that send requests
+// without htmx (e.g. fetch):
+//
+//	<meta name="csrf-token" content="...">
+func Meta(req *http.Request) g.Node {
+	return h.Meta(h.Name("csrf-token"), h.Content(Token(req)))
+}
+
+// HXHeaders renders an hx-headers attribute that adds the token header to
+// every htmx request issued from within the element. Place it on <body>:
+//
+//	h.Body(csrf.HXHeaders(ctx.Req), ...)
+//	// <body hx-headers='{"X-CSRF-Token":"..."}'>
+func HXHeaders(req *http.Request) g.Node {
+	headers, _ := json.Marshal(map[string]string{requestConfig(req).HeaderName: Token(req)})
+	return g.Attr("hx-headers", string(headers))
+}
+
+// isSafeMethod reports whether the method is considered safe (RFC 9110).
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// newToken returns a new random token.
+func newToken() []byte {
+	b := make([]byte, tokenLength)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return b
+}
+
+// cookieToken returns the decoded token from the cookie or nil if missing/invalid.
+func cookieToken(r *http.Request, name string) []byte {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil
+	}
+	token, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil || len(token) != tokenLength {
+		return nil
+	}
+	return token
+}
+
+// mask returns base64(pad || token XOR pad) with a fresh random pad.
+func mask(token []byte) string {
+	pad := newToken()
+	out := make([]byte, 2*tokenLength)
+	copy(out, pad)
+	subtle.XORBytes(out[tokenLength:], token, pad)
+	return base64.RawURLEncoding.EncodeToString(out)
+}
+
+// unmask reverses mask. Returns nil for malformed input.
+func unmask(masked string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(masked)
+	if err != nil || len(b) != 2*tokenLength {
+		return nil
+	}
+	token := make([]byte, tokenLength)
+	subtle.XORBytes(token, b[tokenLength:], b[:tokenLength])
+	return token
+}
+
+// tokensEqual compares tokens in constant time.
+func tokensEqual(a, b []byte) bool {
+	return len(a) == tokenLength && subtle.ConstantTimeCompare(a, b) == 1
+}
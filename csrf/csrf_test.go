@@ -0,0 +1,157 @@
+package csrf
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// issue performs a GET request and returns the cookie and masked token.
+func issue(t *testing.T, mw func(http.Handler) http.Handler) (*http.Cookie, string) {
+	t.Helper()
+
+	var token string
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = Token(r)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName {
+		t.Fatalf("expected %s cookie, got %v", DefaultCookieName, cookies)
+	}
+	if token == "" {
+		t.Fatal("expected token in request context")
+	}
+	return cookies[0], token
+}
+
+// TestProtect tests token validation for unsafe methods
+func TestProtect(t *testing.T) {
+	mw := Protect(Config{})
+	cookie, token := issue(t, mw)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name         string
+		setup        func(r *http.Request)
+		body         string
+		expectedCode int
+	}{
+		{"valid header", func(r *http.Request) {
+			r.AddCookie(cookie)
+			r.Header.Set(DefaultHeaderName, token)
+		}, "", http.StatusOK},
+		{"valid form field", func(r *http.Request) {
+			r.AddCookie(cookie)
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}, url.Values{DefaultFieldName: {token}}.Encode(), http.StatusOK},
+		{"missing cookie", func(r *http.Request) {
+			r.Header.Set(DefaultHeaderName, token)
+		}, "", http.StatusForbidden},
+		{"missing token", func(r *http.Request) {
+			r.AddCookie(cookie)
+		}, "", http.StatusForbidden},
+		{"tampered token", func(r *http.Request) {
+			r.AddCookie(cookie)
+			r.Header.Set(DefaultHeaderName, "x"+token[1:])
+		}, "", http.StatusForbidden},
+		{"garbage token", func(r *http.Request) {
+			r.AddCookie(cookie)
+			r.Header.Set(DefaultHeaderName, "not-a-token")
+		}, "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/save", strings.NewReader(tt.body))
+			tt.setup(req)
+
+			rec := httptest.NewRecorder()
+			mw(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+// TestProtect_MaskedTokens tests that each request gets a different masked token
+func TestProtect_MaskedTokens(t *testing.T) {
+	mw := Protect(Config{})
+	cookie, first := issue(t, mw)
+
+	var second string
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		second = Token(r)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("existing cookie should be reused")
+	}
+	if first == second {
+		t.Error("masked tokens should differ between requests")
+	}
+	if !tokensEqual(unmask(first), unmask(second)) {
+		t.Error("masked tokens should unmask to the same token")
+	}
+}
+
+// TestProtect_HTMXError tests the HTMX-aware error path
+func TestProtect_HTMXError(t *testing.T) {
+	mw := Protect(Config{})
+
+	req := httptest.NewRequest("DELETE", "/item/1", nil)
+	req.Header.Set("HX-Request", "true")
+
+	rec := httptest.NewRecorder()
+	mw(http.NotFoundHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rec.Code)
+	}
+	if rec.Header().Get("HX-Reswap") != "none" {
+		t.Error("expected HX-Reswap: none")
+	}
+
+	var trigger map[string]map[string]any
+	if err := json.Unmarshal([]byte(rec.Header().Get("HX-Trigger")), &trigger); err != nil {
+		t.Fatalf("failed to parse HX-Trigger: %v", err)
+	}
+	if trigger["toast"]["level"] != "error" {
+		t.Errorf("expected error toast, got %v", trigger)
+	}
+}
+
+// TestHelpers tests the gomponents helpers
+func TestHelpers(t *testing.T) {
+	mw := Protect(Config{HeaderName: "X-Token", FieldName: "_token"})
+
+	var input, headers strings.Builder
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = Input(r).Render(&input)
+		_ = HXHeaders(r).Render(&headers)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(input.String(), `type="hidden" name="_token" value="`) {
+		t.Errorf("unexpected input %q", input.String())
+	}
+	if !strings.Contains(headers.String(), `hx-headers="{&#34;X-Token&#34;:&#34;`) {
+		t.Errorf("unexpected hx-headers %q", headers.String())
+	}
+}
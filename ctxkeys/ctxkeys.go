@@ -13,6 +13,32 @@
 //	// In handler
 //	url := view.URLStringChi(ctx.Req, "/login")  // Returns "/app/login"
 //
+// # CSPNonce
+//
+// The CSPNonce constant is used by middleware.CSP to store the per-request
+// Content-Security-Policy nonce. Packages that render inline scripts
+// (hxevents, assets) read it to add the nonce attribute.
+//
+// # CSRFToken
+//
+// The CSRFToken constant is used by the csrf middleware to store the masked
+// CSRF token of the current request (read via handler.Context.CSRFToken).
+//
+// # Origin
+//
+// The Origin constant is used by middleware.Origin to store the public origin
+// (scheme://host) of the request, used by view.AbsoluteURL.
+//
+// # SigningKey
+//
+// The SigningKey constant is used by middleware.URLSigning to provide the
+// HMAC key for view.SignedURL and middleware.RequireSignedURL.
+//
+// # Subject
+//
+// The Subject constant is used by casbinx.Middleware to store the authorized
+// subject of the request (read via casbinx.SubjectFromRequest).
+//
 // # Why a Separate Package?
 //
 // Context keys are defined in a separate package to avoid import cycles between
@@ -23,4 +49,11 @@
 // None - stdlib only.
 package ctxkeys
 
-const BasePath = "basePath"
+const (
+	BasePath   = "basePath"
+	CSPNonce   = "cspNonce"
+	CSRFToken  = "csrfToken"
+	Origin     = "origin"
+	SigningKey = "signingKey"
+	Subject    = "subject"
+)
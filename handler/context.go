@@ -29,9 +29,17 @@
 //
 // The wrapper automatically:
 //   - Creates the Context with request/response
-//   - Handles errors (logs and returns 500)
+//   - Handles errors (logs and writes an HTMX-aware error response)
 //   - Commits accumulated events via HX-Trigger headers
 //
+// # Errors
+//
+// Return *handler.Error to control status code and user-facing message.
+// Errors are HTMX-aware: HTMX requests get an error toast, full-page loads
+// get an error page (see Wrapper.SetErrorPage):
+//
+//	return handler.NewError(http.StatusForbidden, "Not allowed")
+//
 // # Dependencies
 //
 // Requires: stdlib (net/http, log/slog), gomponents
@@ -44,6 +52,7 @@ import (
 
 	g "maragu.dev/gomponents"
 
+	"github.com/axelrhd/hagg-lib/ctxkeys"
 	"github.com/axelrhd/hagg-lib/hxevents"
 	"github.com/axelrhd/hagg-lib/toast"
 )
@@ -90,6 +99,13 @@ func (c *Context) Events() []Event {
 	return c.events
 }
 
+// CSRFToken returns the CSRF token for this request (see csrf package).
+// Returns an empty string if the csrf middleware is not used.
+func (c *Context) CSRFToken() string {
+	token, _ := c.Req.Context().Value(ctxkeys.CSRFToken).(string)
+	return token
+}
+
 // Logger returns the structured logger for this request.
 func (c *Context) Logger() *slog.Logger {
 	return c.logger
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/hxevents"
+)
+
+// Error is an error with an HTTP status code and a user-facing message.
+//
+// Return it from a handler (or pass it to WriteError from middleware) to
+// control the response status and the message shown to the user:
+//
+//	if !found {
+//	    return handler.NewError(http.StatusNotFound, "User not found")
+//	}
+//
+// Errors of other types are treated as 500 Internal Server Error and their
+// message is never shown to the user.
+type Error struct {
+	Status  int    // HTTP status code
+	Message string // Message shown to the user (toast or error page)
+	Err     error  // Underlying error (logged, not shown)
+}
+
+// NewError creates an Error with status and user-facing message.
+func NewError(status int, message string) *Error {
+	return &Error{Status: status, Message: message}
+}
+
+// WrapError creates an Error that wraps an underlying cause.
+func WrapError(status int, message string, err error) *Error {
+	return &Error{Status: status, Message: message, Err: err}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Status, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Status, e.Message)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPageFunc renders the page for full-page (non-HTMX) error responses.
+type ErrorPageFunc func(req *http.Request, status int, message string) g.Node
+
+// DefaultErrorPage renders a minimal standalone HTML error page.
+func DefaultErrorPage(_ *http.Request, status int, message string) g.Node {
+	title := fmt.Sprintf("%d %s", status, http.StatusText(status))
+	return g.Group([]g.Node{
+		g.Raw("<!DOCTYPE html>"),
+		h.HTML(
+			h.Head(
+				h.Meta(h.Charset("utf-8")),
+				h.TitleEl(g.Text(title)),
+			),
+			h.Body(
+				h.H1(g.Text(title)),
+				h.P(g.Text(message)),
+			),
+		),
+	})
+}
+
+// WriteError writes err as an HTMX-aware error response using DefaultErrorPage.
+//
+// Middleware that has no access to a Wrapper can use it directly; prefer
+// Wrapper.Error to use the app's error page and logging.
+func WriteError(res http.ResponseWriter, req *http.Request, err error) {
+	writeError(res, req, err, DefaultErrorPage)
+}
+
+// writeError writes the error response:
+//   - HTMX requests: status code, error toast via HX-Trigger, HX-Reswap: none
+//   - Full-page loads: status code and the rendered error page
+func writeError(res http.ResponseWriter, req *http.Request, err error, page ErrorPageFunc) {
+	status, message := errorStatus(err)
+
+	if hxevents.IsHtmxRequest(req.Header) {
+		ctx := &Context{Res: res, Req: req}
+		ctx.Toast(message).Error().Notify()
+		ctx.commitEvents()
+
+		// htmx does not swap error responses by default; make it explicit
+		res.Header().Set("HX-Reswap", "none")
+		res.WriteHeader(status)
+		return
+	}
+
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.WriteHeader(status)
+	_ = page(req, status, message).Render(res)
+}
+
+// errorStatus extracts status and user-facing message from err.
+func errorStatus(err error) (int, string) {
+	var e *Error
+	if errors.As(err, &e) {
+		message := e.Message
+		if message == "" {
+			message = http.StatusText(e.Status)
+		}
+		return e.Status, message
+	}
+	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+}
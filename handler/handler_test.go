@@ -45,9 +45,11 @@ func TestContext_Render(t *testing.T) {
 	// Create response recorder
 	rec := httptest.NewRecorder()
 
-	// Create context
+	// Create context (Render reads Req to tell HTMX from full-page requests;
+	// a Context without Req never occurs in handlers)
 	ctx := &Context{
 		Res: rec,
+		Req: httptest.NewRequest(http.MethodGet, "/", nil),
 	}
 
 	// Create a simple gomponents node
@@ -225,3 +227,64 @@ func Test_hasPhasePrefix(t *testing.T) {
 		})
 	}
 }
+
+// TestWrapper_Error tests the HTMX-aware error path
+func TestWrapper_Error(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	wrapper := NewWrapper(logger)
+
+	handler := func(ctx *Context) error {
+		ctx.Event("dropped-event", nil)
+		return NewError(http.StatusForbidden, "Not allowed")
+	}
+
+	t.Run("full page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+
+		wrapper.Wrap(handler)(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "Not allowed") {
+			t.Errorf("expected error page with message, got '%s'", rec.Body.String())
+		}
+	})
+
+	t.Run("HTMX request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/test", nil)
+		req.Header.Set("HX-Request", "true")
+
+		wrapper.Wrap(handler)(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", rec.Code)
+		}
+		if rec.Header().Get("HX-Reswap") != "none" {
+			t.Error("expected HX-Reswap: none")
+		}
+
+		hxTrigger := rec.Header().Get("HX-Trigger")
+		if !strings.Contains(hxTrigger, `"message":"Not allowed"`) || !strings.Contains(hxTrigger, `"level":"error"`) {
+			t.Errorf("expected error toast in HX-Trigger, got '%s'", hxTrigger)
+		}
+		if strings.Contains(hxTrigger, "dropped-event") {
+			t.Error("events of a failed handler should not be committed")
+		}
+	})
+
+	t.Run("custom error page", func(t *testing.T) {
+		w := NewWrapper(logger).SetErrorPage(func(req *http.Request, status int, message string) g.Node {
+			return html.P(g.Textf("custom %d", status))
+		})
+
+		rec := httptest.NewRecorder()
+		w.Wrap(handler)(rec, httptest.NewRequest("GET", "/test", nil))
+
+		if rec.Body.String() != "<p>custom 403</p>" {
+			t.Errorf("expected custom error page, got '%s'", rec.Body.String())
+		}
+	})
+}
@@ -15,12 +15,51 @@ type HandlerFunc func(*Context) error
 //   - Centralized error handling
 //   - Automatic event commitment (via hxevents)
 type Wrapper struct {
-	logger *slog.Logger
+	logger    *slog.Logger
+	errorPage ErrorPageFunc
 }
 
 // NewWrapper creates a new handler wrapper with the given logger.
 func NewWrapper(logger *slog.Logger) *Wrapper {
-	return &Wrapper{logger: logger}
+	return &Wrapper{logger: logger, errorPage: DefaultErrorPage}
+}
+
+// SetErrorPage sets the page rendered for full-page error responses.
+// Returns self for method chaining.
+//
+// Example:
+//
+//	wrapper := handler.NewWrapper(logger).SetErrorPage(pages.Error)
+func (w *Wrapper) SetErrorPage(page ErrorPageFunc) *Wrapper {
+	if page == nil {
+		page = DefaultErrorPage
+	}
+	w.errorPage = page
+	return w
+}
+
+// Error logs err and writes an HTMX-aware error response.
+//
+//   - HTMX requests: error toast via HX-Trigger, HX-Reswap: none
+//   - Full-page loads: the configured error page
+//
+// The status code comes from *Error (default 500). Middleware should use
+// this method as its error handler, e.g. csrf.Config{ErrorHandler: wrapper.Error}.
+func (w *Wrapper) Error(res http.ResponseWriter, req *http.Request, err error) {
+	status, _ := errorStatus(err)
+
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	w.logger.Log(req.Context(), level, "handler error",
+		"path", req.URL.Path,
+		"method", req.Method,
+		"status", status,
+		"error", err,
+	)
+
+	writeError(res, req, err, w.errorPage)
 }
 
 // Logger returns the logger instance used by this wrapper.
@@ -34,7 +73,7 @@ func (w *Wrapper) Logger() *slog.Logger {
 // Flow:
 //  1. Create Context with response writer, request, and logger
 //  2. Call the handler
-//  3. If handler returns error: log it and return 500
+//  3. If handler returns error: log it and write an error response (see Error)
 //  4. If handler succeeds: commit events to headers/script
 //
 // Example usage:
@@ -53,12 +92,7 @@ func (w *Wrapper) Wrap(h HandlerFunc) http.HandlerFunc {
 
 		// Call the handler
 		if err := h(ctx); err != nil {
-			w.logger.Error("handler error",
-				"path", req.URL.Path,
-				"method", req.Method,
-				"error", err,
-			)
-			http.Error(res, "Internal Server Error", http.StatusInternalServerError)
+			w.Error(res, req, err)
 			return
 		}
 
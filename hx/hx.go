@@ -0,0 +1,375 @@
+// Package hx provides typed, basePath-aware gomponents attributes for htmx.
+//
+// Request attributes take the request and apply the base path (see
+// middleware.BasePath) automatically, so URLs are always correct regardless
+// of where the app is mounted.
+//
+// # Usage Example
+//
+//	h.Button(
+//	    hx.Post(req, "/htmx/save"),               // hx-post="/app/htmx/save"
+//	    hx.Target("#result"),
+//	    hx.Swap(hx.OuterHTML, hx.Transition()),   // hx-swap="outerHTML transition:true"
+//	    hx.Vals(map[string]any{"id": 42}),        // hx-vals="{&#34;id&#34;:42}"
+//	    hx.Confirm("Save changes?"),
+//	    g.Text("Save"),
+//	)
+//
+//	h.Input(
+//	    h.Name("q"),
+//	    hx.Get(req, "/htmx/search"),
+//	    hx.Trigger(
+//	        hx.Event("keyup").Changed().Delay(300*time.Millisecond),
+//	        hx.Event("search"),
+//	    ),                                        // hx-trigger="keyup changed delay:300ms, search"
+//	)
+//
+// # Dependencies
+//
+// Requires: stdlib (net/http, encoding/json), gomponents, view package
+package hx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	g "maragu.dev/gomponents"
+
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// Request attributes (basePath-aware).
+
+// Get returns hx-get with the basePath-aware URL of p.
+func Get(req *http.Request, p string) g.Node { return g.Attr("hx-get", view.URLString(req, p)) }
+
+// Post returns hx-post with the basePath-aware URL of p.
+func Post(req *http.Request, p string) g.Node { return g.Attr("hx-post", view.URLString(req, p)) }
+
+// Put returns hx-put with the basePath-aware URL of p.
+func Put(req *http.Request, p string) g.Node { return g.Attr("hx-put", view.URLString(req, p)) }
+
+// Patch returns hx-patch with the basePath-aware URL of p.
+func Patch(req *http.Request, p string) g.Node { return g.Attr("hx-patch", view.URLString(req, p)) }
+
+// Delete returns hx-delete with the basePath-aware URL of p.
+func Delete(req *http.Request, p string) g.Node { return g.Attr("hx-delete", view.URLString(req, p)) }
+
+// PushURL returns hx-push-url with the basePath-aware URL of p.
+func PushURL(req *http.Request, p string) g.Node {
+	return g.Attr("hx-push-url", view.URLString(req, p))
+}
+
+// PushURLEnabled returns hx-push-url="true" or "false"
+// (true pushes the request URL into the browser history).
+func PushURLEnabled(enabled bool) g.Node {
+	return g.Attr("hx-push-url", strconv.FormatBool(enabled))
+}
+
+// ReplaceURL returns hx-replace-url with the basePath-aware URL of p.
+func ReplaceURL(req *http.Request, p string) g.Node {
+	return g.Attr("hx-replace-url", view.URLString(req, p))
+}
+
+// ReplaceURLEnabled returns hx-replace-url="true" or "false".
+func ReplaceURLEnabled(enabled bool) g.Node {
+	return g.Attr("hx-replace-url", strconv.FormatBool(enabled))
+}
+
+// Targeting and selection.
+
+// Target returns hx-target (CSS selector or "this", "closest tr", "next .x", ...).
+func Target(selector string) g.Node { return g.Attr("hx-target", selector) }
+
+// Select returns hx-select (selects content from the response).
+func Select(selector string) g.Node { return g.Attr("hx-select", selector) }
+
+// SelectOOB returns hx-select-oob.
+func SelectOOB(selectors ...string) g.Node {
+	return g.Attr("hx-select-oob", strings.Join(selectors, ","))
+}
+
+// SwapOOB returns hx-swap-oob (e.g. "true", "outerHTML:#id").
+func SwapOOB(value string) g.Node { return g.Attr("hx-swap-oob", value) }
+
+// Include returns hx-include (additional elements to include in the request).
+func Include(selector string) g.Node { return g.Attr("hx-include", selector) }
+
+// Indicator returns hx-indicator (element that gets the htmx-request class).
+func Indicator(selector string) g.Node { return g.Attr("hx-indicator", selector) }
+
+// DisabledElt returns hx-disabled-elt (elements disabled during the request).
+func DisabledElt(selector string) g.Node { return g.Attr("hx-disabled-elt", selector) }
+
+// Values and headers.
+
+// Vals returns hx-vals with v encoded as JSON.
+// v must encode to a JSON object (struct or map); htmx ignores anything else.
+// A nil or empty value (nil map or pointer, empty map) renders nothing.
+//
+// Panics if v cannot be encoded (e.g. contains a func) or encodes to an
+// array or scalar. Both depend on the type of v, not on its contents, so
+// the first render of the page shows the mistake.
+//
+// Example (the attribute value is HTML-escaped; the browser sees
+// {"id":42,"name":"O'Brien"}):
+//
+//	hx.Vals(map[string]any{"id": 42, "name": `O'Brien`})
+//	// hx-vals="{&#34;id&#34;:42,&#34;name&#34;:&#34;O&#39;Brien&#34;}"
+func Vals(v any) g.Node {
+	s := mustJSON("hx-vals", v)
+	if s == "null" || s == "{}" {
+		return nil
+	}
+	if !strings.HasPrefix(s, "{") {
+		panic(fmt.Sprintf("hx: hx-vals must be a JSON object, got %s", s))
+	}
+	return g.Attr("hx-vals", s)
+}
+
+// Headers returns hx-headers with the given request headers encoded as JSON.
+func Headers(headers map[string]string) g.Node {
+	return g.Attr("hx-headers", mustJSON("hx-headers", headers))
+}
+
+// Params returns hx-params ("*", "none", "not a,b" or "a,b").
+func Params(value string) g.Node { return g.Attr("hx-params", value) }
+
+// Encoding returns hx-encoding (e.g. "multipart/form-data" for file uploads).
+func Encoding(enctype string) g.Node { return g.Attr("hx-encoding", enctype) }
+
+// User interaction.
+
+// Confirm returns hx-confirm (browser confirm dialog before the request).
+func Confirm(message string) g.Node { return g.Attr("hx-confirm", message) }
+
+// Prompt returns hx-prompt (value is sent in the HX-Prompt header).
+func Prompt(message string) g.Node { return g.Attr("hx-prompt", message) }
+
+// Behavior.
+
+// Boost returns hx-boost="true" or "false".
+func Boost(enabled bool) g.Node { return g.Attr("hx-boost", strconv.FormatBool(enabled)) }
+
+// Sync returns hx-sync (e.g. Sync("closest form", "abort")).
+// An empty strategy uses the htmx default (drop).
+func Sync(selector, strategy string) g.Node {
+	if strategy == "" {
+		return g.Attr("hx-sync", selector)
+	}
+	return g.Attr("hx-sync", selector+":"+strategy)
+}
+
+// Ext returns hx-ext (comma-separated extension names).
+func Ext(names ...string) g.Node { return g.Attr("hx-ext", strings.Join(names, ",")) }
+
+// Disinherit returns hx-disinherit ("*" or attribute names).
+func Disinherit(attrs ...string) g.Node { return g.Attr("hx-disinherit", strings.Join(attrs, " ")) }
+
+// Inherit returns hx-inherit ("*" or attribute names).
+func Inherit(attrs ...string) g.Node { return g.Attr("hx-inherit", strings.Join(attrs, " ")) }
+
+// Validate returns hx-validate="true" (validate forms before requests).
+func Validate() g.Node { return g.Attr("hx-validate", "true") }
+
+// Preserve returns hx-preserve (keep the element unchanged between requests).
+func Preserve() g.Node { return g.Attr("hx-preserve", "true") }
+
+// Disable returns hx-disable (disables htmx processing for the element and children).
+func Disable() g.Node { return g.Attr("hx-disable") }
+
+// History returns hx-history="false" (prevents sensitive pages from being cached).
+func History(enabled bool) g.Node { return g.Attr("hx-history", strconv.FormatBool(enabled)) }
+
+// HistoryElt returns hx-history-elt (element snapshotted for history).
+func HistoryElt() g.Node { return g.Attr("hx-history-elt") }
+
+// On returns hx-on:<event> with an inline handler.
+// For htmx events use a leading ":" (On(":after-request", ...) renders hx-on::after-request).
+func On(event, script string) g.Node { return g.Attr("hx-on:"+event, script) }
+
+// Swap
+
+// SwapStyle is the swap strategy of hx-swap.
+type SwapStyle string
+
+// Swap styles supported by htmx.
+const (
+	InnerHTML   SwapStyle = "innerHTML"
+	OuterHTML   SwapStyle = "outerHTML"
+	TextContent SwapStyle = "textContent"
+	BeforeBegin SwapStyle = "beforebegin"
+	AfterBegin  SwapStyle = "afterbegin"
+	BeforeEnd   SwapStyle = "beforeend"
+	AfterEnd    SwapStyle = "afterend"
+	SwapDelete  SwapStyle = "delete"
+	SwapNone    SwapStyle = "none"
+)
+
+// SwapModifier is a modifier of hx-swap (e.g. "transition:true").
+type SwapModifier string
+
+// Transition enables the View Transitions API for the swap.
+func Transition() SwapModifier { return "transition:true" }
+
+// SwapDelay delays the swap.
+func SwapDelay(d time.Duration) SwapModifier { return SwapModifier("swap:" + duration(d)) }
+
+// SettleDelay changes the settle delay.
+func SettleDelay(d time.Duration) SwapModifier { return SwapModifier("settle:" + duration(d)) }
+
+// IgnoreTitle ignores <title> in the response.
+func IgnoreTitle() SwapModifier { return "ignoreTitle:true" }
+
+// Scroll scrolls the target to "top" or "bottom".
+// An optional selector scrolls another element instead.
+func Scroll(position string, selector ...string) SwapModifier {
+	return SwapModifier("scroll:" + withSelector(position, selector))
+}
+
+// Show scrolls the target (or selector) into view at "top" or "bottom".
+func Show(position string, selector ...string) SwapModifier {
+	return SwapModifier("show:" + withSelector(position, selector))
+}
+
+// FocusScroll controls scrolling to focused elements after the swap.
+func FocusScroll(enabled bool) SwapModifier {
+	return SwapModifier("focus-scroll:" + strconv.FormatBool(enabled))
+}
+
+// Swap returns hx-swap with style and modifiers.
+//
+// Example:
+//
+//	hx.Swap(hx.OuterHTML, hx.SettleDelay(100*time.Millisecond), hx.Scroll("top"))
+//	// hx-swap="outerHTML settle:100ms scroll:top"
+func Swap(style SwapStyle, modifiers ...SwapModifier) g.Node {
+	parts := []string{string(style)}
+	for _, m := range modifiers {
+		parts = append(parts, string(m))
+	}
+	return g.Attr("hx-swap", strings.Join(parts, " "))
+}
+
+// Trigger
+
+// TriggerSpec is a single trigger of hx-trigger. Create it with Event or Every.
+type TriggerSpec struct {
+	event     string
+	filter    string
+	modifiers []string
+}
+
+// Event starts a trigger for a DOM event (e.g. "click", "keyup", "revealed").
+func Event(name string) *TriggerSpec { return &TriggerSpec{event: name} }
+
+// Every starts a polling trigger ("every 2s").
+func Every(d time.Duration) *TriggerSpec { return &TriggerSpec{event: "every " + duration(d)} }
+
+// Load is the "load" trigger (fires once when the element is loaded).
+func Load() *TriggerSpec { return Event("load") }
+
+// Revealed is the "revealed" trigger (fires when scrolled into view).
+func Revealed() *TriggerSpec { return Event("revealed") }
+
+// Filter adds an event filter expression (e.g. "ctrlKey", "key=='Enter'").
+// Returns self for method chaining.
+func (t *TriggerSpec) Filter(expr string) *TriggerSpec {
+	t.filter = expr
+	return t
+}
+
+// Once triggers only once.
+// Returns self for method chaining.
+func (t *TriggerSpec) Once() *TriggerSpec { return t.mod("once") }
+
+// Changed triggers only if the element value changed.
+// Returns self for method chaining.
+func (t *TriggerSpec) Changed() *TriggerSpec { return t.mod("changed") }
+
+// Delay waits d before issuing the request; new events reset the timer.
+// Returns self for method chaining.
+func (t *TriggerSpec) Delay(d time.Duration) *TriggerSpec { return t.mod("delay:" + duration(d)) }
+
+// Throttle ignores new events for d after a request.
+// Returns self for method chaining.
+func (t *TriggerSpec) Throttle(d time.Duration) *TriggerSpec {
+	return t.mod("throttle:" + duration(d))
+}
+
+// From listens for the event on another element (CSS selector, "document", "window").
+// Returns self for method chaining.
+func (t *TriggerSpec) From(selector string) *TriggerSpec { return t.mod("from:" + selector) }
+
+// Target only triggers if the event target matches the selector.
+// Returns self for method chaining.
+func (t *TriggerSpec) Target(selector string) *TriggerSpec { return t.mod("target:" + selector) }
+
+// Consume stops the event from triggering htmx requests on parents.
+// Returns self for method chaining.
+func (t *TriggerSpec) Consume() *TriggerSpec { return t.mod("consume") }
+
+// Queue sets the queueing strategy ("first", "last", "all", "none").
+// Returns self for method chaining.
+func (t *TriggerSpec) Queue(strategy string) *TriggerSpec { return t.mod("queue:" + strategy) }
+
+// String returns the trigger definition as used in hx-trigger.
+func (t *TriggerSpec) String() string {
+	s := t.event
+	if t.filter != "" {
+		s += "[" + t.filter + "]"
+	}
+	if len(t.modifiers) > 0 {
+		s += " " + strings.Join(t.modifiers, " ")
+	}
+	return s
+}
+
+// mod appends a modifier.
+func (t *TriggerSpec) mod(m string) *TriggerSpec {
+	t.modifiers = append(t.modifiers, m)
+	return t
+}
+
+// Trigger returns hx-trigger with one or more triggers.
+//
+// Example:
+//
+//	hx.Trigger(hx.Event("click").Once(), hx.Every(5*time.Second))
+//	// hx-trigger="click once, every 5s"
+func Trigger(specs ...*TriggerSpec) g.Node {
+	parts := make([]string, len(specs))
+	for i, s := range specs {
+		parts[i] = s.String()
+	}
+	return g.Attr("hx-trigger", strings.Join(parts, ", "))
+}
+
+// duration formats d for htmx ("500ms", "2s").
+func duration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
+
+// withSelector formats "position" or "selector:position".
+func withSelector(position string, selector []string) string {
+	if len(selector) > 0 && selector[0] != "" {
+		return selector[0] + ":" + position
+	}
+	return position
+}
+
+// mustJSON encodes v as JSON for an attribute value.
+func mustJSON(attr string, v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("hx: encode %s: %v", attr, err))
+	}
+	return string(b)
+}
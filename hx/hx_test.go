@@ -0,0 +1,121 @@
+package hx
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	g "maragu.dev/gomponents"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// render renders a node to a string.
+func render(t *testing.T, n g.Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := n.Render(&buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	return buf.String()
+}
+
+// TestAttributes tests attribute rendering
+func TestAttributes(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxkeys.BasePath, "/app"))
+
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{"get", Get(req, "/items"), ` hx-get="/app/items"`},
+		{"post", Post(req, "htmx/save"), ` hx-post="/app/htmx/save"`},
+		{"put", Put(req, "/items/1"), ` hx-put="/app/items/1"`},
+		{"patch", Patch(req, "/items/1"), ` hx-patch="/app/items/1"`},
+		{"delete", Delete(req, "/items/1?force=1"), ` hx-delete="/app/items/1?force=1"`},
+		{"push url", PushURL(req, "/items"), ` hx-push-url="/app/items"`},
+		{"push url enabled", PushURLEnabled(true), ` hx-push-url="true"`},
+		{"target", Target("closest tr"), ` hx-target="closest tr"`},
+		{"indicator", Indicator("#spinner"), ` hx-indicator="#spinner"`},
+		{"confirm", Confirm(`Delete "x"?`), ` hx-confirm="Delete &#34;x&#34;?"`},
+		{"swap", Swap(OuterHTML), ` hx-swap="outerHTML"`},
+		{"swap modifiers", Swap(BeforeEnd, Transition(), SwapDelay(100*time.Millisecond), SettleDelay(time.Second), Scroll("bottom")),
+			` hx-swap="beforeend transition:true swap:100ms settle:1s scroll:bottom"`},
+		{"swap show selector", Swap(InnerHTML, Show("top", "#list")), ` hx-swap="innerHTML show:#list:top"`},
+		{"trigger", Trigger(Event("click")), ` hx-trigger="click"`},
+		{"trigger modifiers", Trigger(Event("keyup").Changed().Delay(300*time.Millisecond), Event("search")),
+			` hx-trigger="keyup changed delay:300ms, search"`},
+		{"trigger filter", Trigger(Event("keyup").Filter("key=='Enter'").From("body").Once()),
+			` hx-trigger="keyup[key==&#39;Enter&#39;] from:body once"`},
+		{"trigger polling", Trigger(Load(), Every(2*time.Second)), ` hx-trigger="load, every 2s"`},
+		{"vals", Vals(map[string]any{"id": 42, "name": "a&b"}), ` hx-vals="{&#34;id&#34;:42,&#34;name&#34;:&#34;a\u0026b&#34;}"`},
+		{"headers", Headers(map[string]string{"X-A": "1"}), ` hx-headers="{&#34;X-A&#34;:&#34;1&#34;}"`},
+		{"sync", Sync("closest form", "abort"), ` hx-sync="closest form:abort"`},
+		{"on", On(":after-request", "this.reset()"), ` hx-on::after-request="this.reset()"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, tt.node); got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestVals_Panics tests that unencodable values and non-objects panic
+func TestVals_Panics(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"unencodable", map[string]any{"f": func() {}}},
+		{"array", []int{1, 2}},
+		{"string", "id=42"},
+		{"number", 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %v", tt.value)
+				}
+			}()
+			Vals(tt.value)
+		})
+	}
+}
+
+// TestVals_Empty tests that nil and empty values render nothing
+func TestVals_Empty(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"nil", nil},
+		{"nil map", map[string]any(nil)},
+		{"empty map", map[string]any{}},
+		{"nil pointer", (*struct{ ID int })(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, g.El("div", Vals(tt.value))); got != "<div></div>" {
+				t.Errorf("expected no attribute, got %s", got)
+			}
+		})
+	}
+}
+
+// TestVals_DocExample tests the rendering shown in the Vals documentation
+func TestVals_DocExample(t *testing.T) {
+	got := render(t, Vals(map[string]any{"id": 42, "name": `O'Brien`}))
+	if want := ` hx-vals="{&#34;id&#34;:42,&#34;name&#34;:&#34;O&#39;Brien&#34;}"`; got != want {
+		t.Errorf("got %s, expected %s", got, want)
+	}
+}
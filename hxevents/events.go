@@ -42,6 +42,14 @@
 //
 // Events without a phase prefix are ignored.
 //
+// # Toast Rendering (Full-Page Loads)
+//
+// RenderToasts delegates to a pluggable ToastRenderer, selected once at setup:
+//
+//	hxevents.SetToastRenderer(hxevents.SurrealToasts)  // default: showToast() + me().remove()
+//	hxevents.SetToastRenderer(hxevents.VanillaToasts)  // CustomEvent on document.body
+//	hxevents.SetToastRenderer(hxevents.AlpineToasts)   // x-init="$dispatch('toast', ...)"
+//
 // # Dependencies
 //
 // Requires: stdlib (net/http, encoding/json), gomponents
@@ -1,10 +1,17 @@
 package hxevents
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	g "maragu.dev/gomponents"
+
+	"github.com/axelrhd/hagg-lib/assets"
+	"github.com/axelrhd/hagg-lib/ctxkeys"
 )
 
 // TestIsHtmxRequest tests HTMX request detection
@@ -256,3 +263,150 @@ func TestPhaseConstants(t *testing.T) {
 		t.Errorf("AfterSettle phase should be 'HX-Trigger-After-Settle', got '%s'", AfterSettle)
 	}
 }
+
+// TestRenderToasts_Renderers tests the built-in toast renderers
+func TestRenderToasts_Renderers(t *testing.T) {
+	defer SetToastRenderer(nil)
+
+	events := []Event{
+		{Name: "toast", Payload: map[string]any{"message": "Saved", "level": "success"}},
+		{Name: "HX-Trigger:toast", Payload: map[string]any{"message": "htmx only"}},
+	}
+
+	tests := []struct {
+		name     string
+		renderer ToastRenderer
+		contains []string
+	}{
+		{"default (nil)", nil, []string{`showToast({"level":"success","message":"Saved"})`, `me().remove()`}},
+		{"surreal", SurrealToasts, []string{`showToast({"level":"success","message":"Saved"})`, `me().remove()`}},
+		{"vanilla", VanillaToasts, []string{`new CustomEvent("toast",{detail:{"level":"success","message":"Saved"}`, `DOMContentLoaded`}},
+		{"alpine", AlpineToasts, []string{`x-data`, `x-init="$dispatch(&#39;toast&#39;, {&#34;level&#34;:&#34;success&#34;,&#34;message&#34;:&#34;Saved&#34;}); $el.remove()"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetToastRenderer(tt.renderer)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			node := RenderToasts(req, events)
+			if node == nil {
+				t.Fatal("RenderToasts() returned nil")
+			}
+
+			var sb strings.Builder
+			if err := node.Render(&sb); err != nil {
+				t.Fatalf("Render() failed: %v", err)
+			}
+			html := sb.String()
+
+			for _, want := range tt.contains {
+				if !strings.Contains(html, want) {
+					t.Errorf("expected output to contain %q, got %q", want, html)
+				}
+			}
+			if strings.Contains(html, "htmx only") {
+				t.Error("phase-prefixed events should not be rendered")
+			}
+		})
+	}
+
+	t.Run("custom renderer func", func(t *testing.T) {
+		var got []byte
+		SetToastRenderer(ToastRendererFunc(func(req *http.Request, payload []byte) g.Node {
+			got = payload
+			return g.Text("custom")
+		}))
+
+		RenderToasts(httptest.NewRequest("GET", "/", nil), events)
+		if string(got) != `{"level":"success","message":"Saved"}` {
+			t.Errorf("unexpected payload %q", got)
+		}
+	})
+
+	t.Run("HTMX request - nothing rendered", func(t *testing.T) {
+		SetToastRenderer(VanillaToasts)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("HX-Request", "true")
+		if RenderToasts(req, events) != nil {
+			t.Error("RenderToasts() should return nil for HTMX requests")
+		}
+	})
+}
+
+// TestRender_CSPNonce tests that rendered scripts carry the CSP nonce
+func TestRender_CSPNonce(t *testing.T) {
+	defer SetToastRenderer(nil)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxkeys.CSPNonce, "abc123"))
+
+	events := []Event{
+		{Name: "toast", Payload: map[string]any{"message": "Hi"}},
+	}
+
+	tests := []struct {
+		name  string
+		node  func() g.Node
+		count int
+	}{
+		{"initial events", func() g.Node { return RenderInitialEvents(req, events) }, 1},
+		{"surreal toasts", func() g.Node { SetToastRenderer(SurrealToasts); return RenderToasts(req, events) }, 2},
+		{"vanilla toasts", func() g.Node { SetToastRenderer(VanillaToasts); return RenderToasts(req, events) }, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.node().Render(&sb); err != nil {
+				t.Fatalf("Render() failed: %v", err)
+			}
+
+			got := strings.Count(sb.String(), `nonce="abc123"`)
+			if got != tt.count {
+				t.Errorf("expected %d nonce attributes, got %d in %q", tt.count, got, sb.String())
+			}
+		})
+	}
+}
+
+// TestRenderToasts_DeferredScripts tests that toasts rendered next to the
+// deferred assets.Scripts wait for DOMContentLoaded before showing
+func TestRenderToasts_DeferredScripts(t *testing.T) {
+	defer SetToastRenderer(nil)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	events := []Event{
+		{Name: "toast", Payload: map[string]any{"message": "Saved"}},
+	}
+
+	for _, r := range []struct {
+		name     string
+		renderer ToastRenderer
+	}{
+		{"surreal", SurrealToasts},
+		{"vanilla", VanillaToasts},
+	} {
+		t.Run(r.name, func(t *testing.T) {
+			SetToastRenderer(r.renderer)
+
+			var sb strings.Builder
+			page := g.Group([]g.Node{assets.Scripts(req), RenderToasts(req, events)})
+			if err := page.Render(&sb); err != nil {
+				t.Fatalf("Render() failed: %v", err)
+			}
+			html := sb.String()
+
+			if !strings.Contains(html, "toast.") || !strings.Contains(html, " defer") {
+				t.Fatalf("expected deferred toast.js, got %q", html)
+			}
+			if strings.Contains(html, "<script>showToast(") {
+				t.Errorf("showToast must not run before deferred scripts, got %q", html)
+			}
+			if !strings.Contains(html, `d.addEventListener("DOMContentLoaded",f)`) {
+				t.Errorf("expected DOMContentLoaded wrapper, got %q", html)
+			}
+		})
+	}
+}
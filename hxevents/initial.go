@@ -2,7 +2,6 @@ package hxevents
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -23,6 +22,8 @@ import (
 // The frontend processes this script on DOMContentLoaded and triggers the same
 // event handlers as HX-Trigger events, creating a unified event system.
 //
+// If middleware.CSP is used, the script carries the request's nonce.
+//
 // Example output:
 //
 //	<script type="application/json" id="initial-events">
@@ -65,21 +66,23 @@ func RenderInitialEvents(req *http.Request, events []Event) g.Node {
 	return h.Script(
 		h.Type("application/json"),
 		h.ID("initial-events"),
+		nonceAttr(req),
 		g.Raw(string(jsonData)),
 	)
 }
 
 // RenderToasts creates self-destructing toast elements for full-page loads.
-// Uses Surreal.js pattern: Script tag with showToast() call + me().remove().
+// Each toast is rendered by the renderer selected with SetToastRenderer
+// (default: SurrealToasts - showToast() call + me().remove()).
 //
 // Returns nil if:
 //   - This is an HTMX request (toasts are sent via HX-Trigger header)
 //   - There are no toast events
 //
-// Example output:
+// Example output (SurrealToasts):
 //
 //	<div>
-//	  <script>showToast({"message":"Welcome!","level":"success",...})</script>
+//	  <script>(function(d){...showToast({"message":"Welcome!","level":"success",...})...})(document)</script>
 //	  <script>me().remove()</script>
 //	</div>
 func RenderToasts(req *http.Request, events []Event) g.Node {
@@ -112,10 +115,7 @@ func RenderToasts(req *http.Request, events []Event) g.Node {
 		}
 
 		// Create self-destructing toast element
-		toasts = append(toasts, h.Div(
-			h.Script(g.Raw(fmt.Sprintf(`showToast(%s)`, toastJSON))),
-			h.Script(g.Raw(`me().remove()`)),
-		))
+		toasts = append(toasts, toastRenderer.RenderToast(req, toastJSON))
 	}
 
 	// Return nil if no toasts (g.Group with nil elements causes issues)
@@ -0,0 +1,22 @@
+package hxevents
+
+import (
+	"net/http"
+
+	g "maragu.dev/gomponents"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// nonceAttr returns the CSP nonce attribute for inline scripts.
+// Returns nil if no nonce is set (middleware.CSP not used).
+func nonceAttr(req *http.Request) g.Node {
+	if req == nil {
+		return nil
+	}
+	nonce, _ := req.Context().Value(ctxkeys.CSPNonce).(string)
+	if nonce == "" {
+		return nil
+	}
+	return g.Attr("nonce", nonce)
+}
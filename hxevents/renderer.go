@@ -0,0 +1,101 @@
+package hxevents
+
+import (
+	"fmt"
+	"net/http"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// ToastRenderer renders a single toast for a full-page load.
+//
+// The payload is the JSON-encoded toast (see toast.Toast.Notify). It is
+// produced by encoding/json and therefore safe to embed in a <script> tag
+// or an HTML attribute. Inline scripts must carry the CSP nonce of req
+// (see middleware.CSP).
+//
+// Select the renderer once at setup with SetToastRenderer.
+type ToastRenderer interface {
+	RenderToast(req *http.Request, payload []byte) g.Node
+}
+
+// ToastRendererFunc adapts an ordinary function to the ToastRenderer interface.
+type ToastRendererFunc func(req *http.Request, payload []byte) g.Node
+
+// RenderToast calls f(req, payload).
+func (f ToastRendererFunc) RenderToast(req *http.Request, payload []byte) g.Node {
+	return f(req, payload)
+}
+
+// Built-in toast renderers.
+var (
+	// SurrealToasts calls showToast() once the DOM is ready and removes itself
+	// with Surreal's me(). Requires surreal.js and toast.js. This is the default.
+	//
+	//	<div>
+	//	  <script>(function(d){...showToast({...})...})(document)</script>
+	//	  <script>me().remove()</script>
+	//	</div>
+	SurrealToasts ToastRenderer = ToastRendererFunc(renderSurrealToast)
+
+	// VanillaToasts dispatches a "toast" CustomEvent on document.body once the
+	// DOM is ready. Requires only toast.js (or any listener for "toast").
+	//
+	//	<script>(function(d){...new CustomEvent("toast",{detail:{...}})...})(document)</script>
+	VanillaToasts ToastRenderer = ToastRendererFunc(renderVanillaToast)
+
+	// AlpineToasts dispatches a "toast" event via Alpine's $dispatch and
+	// removes the element afterwards. Requires Alpine.js and toast.js.
+	//
+	//	<div x-data x-init="$dispatch('toast', {...}); $el.remove()"></div>
+	AlpineToasts ToastRenderer = ToastRendererFunc(renderAlpineToast)
+)
+
+// toastRenderer is the renderer used by RenderToasts.
+var toastRenderer = SurrealToasts
+
+// SetToastRenderer selects the renderer used by RenderToasts.
+//
+// Call once during application setup, before serving requests.
+// Passing nil restores the default (SurrealToasts).
+//
+// Example:
+//
+//	hxevents.SetToastRenderer(hxevents.VanillaToasts)
+func SetToastRenderer(r ToastRenderer) {
+	if r == nil {
+		r = SurrealToasts
+	}
+	toastRenderer = r
+}
+
+// renderSurrealToast renders the Surreal.js pattern (script + me().remove()).
+// The showToast call waits for DOMContentLoaded so a deferred toast.js is loaded.
+func renderSurrealToast(req *http.Request, payload []byte) g.Node {
+	return h.Div(
+		h.Script(nonceAttr(req), g.Raw(fmt.Sprintf(
+			`(function(d){var f=function(){showToast(%s)};if(d.readyState==="loading"){d.addEventListener("DOMContentLoaded",f)}else{f()}})(document)`,
+			payload,
+		))),
+		h.Script(nonceAttr(req), g.Raw(`me().remove()`)),
+	)
+}
+
+// renderVanillaToast renders a self-removing script that dispatches a CustomEvent.
+// The dispatch waits for DOMContentLoaded so deferred listeners are registered.
+func renderVanillaToast(req *http.Request, payload []byte) g.Node {
+	return h.Script(nonceAttr(req), g.Raw(fmt.Sprintf(
+		`(function(d){var s=d.currentScript,f=function(){d.body.dispatchEvent(new CustomEvent("toast",{detail:%s,bubbles:true}))};if(d.readyState==="loading"){d.addEventListener("DOMContentLoaded",f)}else{f()}if(s){s.remove()}})(document)`,
+		payload,
+	)))
+}
+
+// renderAlpineToast renders an element that dispatches the toast on Alpine init.
+// The attribute value is HTML-escaped by gomponents.
+func renderAlpineToast(_ *http.Request, payload []byte) g.Node {
+	return h.Div(
+		g.Attr("x-data"),
+		g.Attr("x-init", fmt.Sprintf(`$dispatch('toast', %s); $el.remove()`, payload)),
+	)
+}
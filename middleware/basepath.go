@@ -15,14 +15,47 @@
 //	// In handlers
 //	url := view.URLString(req, "/login")  // Returns "/app/login"
 //
+// # BasePath from X-Forwarded-Prefix
+//
+// BasePathFromHeader reads the base path per request from a header set by
+// trusted reverse proxies, so one binary can be mounted under different prefixes:
+//
+//	r.Use(middleware.BasePathFromHeader(middleware.ForwardedPrefixConfig{
+//	    TrustedProxies: middleware.MustTrustedProxies("10.0.0.0/8"),
+//	}))
+//
+// # Prefix Stripping
+//
+// StripBasePath is for proxies that forward the full path ("/app/login"):
+// it strips the base path before routing, so routes stay prefix-free:
+//
+//	r.Use(middleware.StripBasePath("/app"))
+//	r.Get("/login", ...)  // matches /app/login
+//
+// # CSP
+//
+// CSP sets a Content-Security-Policy with a per-request nonce. Scripts
+// rendered by hagg-lib carry the nonce automatically.
+//
+//	r.Use(middleware.CSP(middleware.DefaultCSPConfig()))
+//
+// # Signed URLs
+//
+// URLSigning provides the key for view.SignedURL; RequireSignedURL verifies
+// the links and reports failures through handler.Error.
+//
 // # Dependencies
 //
-// Requires: stdlib (net/http, context), ctxkeys package
+// Requires: stdlib (net/http, context), ctxkeys package; signed URLs also
+// use the handler and view packages
 package middleware
 
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/axelrhd/hagg-lib/ctxkeys"
 )
@@ -43,3 +76,165 @@ func BasePath(base string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// StripBasePath is a middleware for proxies that forward requests without
+// stripping the prefix (e.g. "/app/login" instead of "/login").
+//
+// It removes base from r.URL.Path and r.URL.RawPath before routing and
+// injects the basePath into the request context like BasePath. Requests
+// outside the prefix get 404 Not Found.
+//
+// Panics if base is not a valid path (static configuration error).
+//
+// Example:
+//
+//	r.Use(middleware.StripBasePath("/app"))
+//	r.Get("/login", ...)  // serves /app/login
+//	// view.URLString(req, "/login") returns "/app/login"
+func StripBasePath(base string) func(http.Handler) http.Handler {
+	escaped, ok := normalizeBasePath(base)
+	if !ok {
+		panic("middleware: invalid base path " + base)
+	}
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		panic("middleware: invalid base path " + base + ": " + err.Error())
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := stripPrefix(r.URL.Path, unescaped)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+
+			rawPath := ""
+			if r.URL.RawPath != "" {
+				rawPath, ok = stripPrefix(r.URL.RawPath, escaped)
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+			}
+
+			// Shallow copy like http.StripPrefix - never modify the original request
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = p
+			r2.URL.RawPath = rawPath
+
+			ctx := context.WithValue(r.Context(), ctxkeys.BasePath, escaped)
+			next.ServeHTTP(w, r2.WithContext(ctx))
+		})
+	}
+}
+
+// stripPrefix removes prefix from p on a segment boundary.
+// "/app/login" -> "/login", "/app" -> "/", "/apple" -> not ok.
+func stripPrefix(p, prefix string) (string, bool) {
+	if prefix == "" {
+		return p, true
+	}
+	rest, ok := strings.CutPrefix(p, prefix)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case rest == "":
+		return "/", true
+	case rest[0] == '/':
+		return rest, true
+	}
+	return "", false
+}
+
+// DefaultForwardedPrefixHeader is the header read by BasePathFromHeader by default.
+const DefaultForwardedPrefixHeader = "X-Forwarded-Prefix"
+
+// ForwardedPrefixConfig configures BasePathFromHeader.
+type ForwardedPrefixConfig struct {
+	// TrustedProxies whose prefix header is accepted. Required - without it
+	// the header is ignored and Fallback is used.
+	TrustedProxies *TrustedProxies
+
+	// Header to read. Default: "X-Forwarded-Prefix".
+	Header string
+
+	// Fallback is used for direct requests, untrusted peers and invalid or
+	// missing headers. Default: "" (no base path).
+	Fallback string
+}
+
+// BasePathFromHeader is a middleware that derives the basePath from a
+// forwarded prefix header (X-Forwarded-Prefix by default).
+//
+// The header is only honored for requests from cfg.TrustedProxies. The value
+// is normalized ("app/" -> "/app", "/" -> "") and rejected if it is not a
+// plain path. The result is stored under ctxkeys.BasePath, so view.URLString
+// works unchanged.
+//
+// Example:
+//
+//	// Proxy A: X-Forwarded-Prefix: /app     -> view.URLString(req, "/login") == "/app/login"
+//	// Proxy B: X-Forwarded-Prefix: /tools/x -> view.URLString(req, "/login") == "/tools/x/login"
+//	r.Use(middleware.BasePathFromHeader(middleware.ForwardedPrefixConfig{
+//	    TrustedProxies: middleware.MustTrustedProxies("10.0.0.0/8"),
+//	}))
+func BasePathFromHeader(cfg ForwardedPrefixConfig) func(http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = DefaultForwardedPrefixHeader
+	}
+	fallback, ok := normalizeBasePath(cfg.Fallback)
+	if !ok {
+		panic("middleware: invalid fallback base path " + cfg.Fallback)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			base := fallback
+			if v := cfg.TrustedProxies.Header(r, cfg.Header); v != "" {
+				if p, ok := normalizeBasePath(v); ok {
+					base = p
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), ctxkeys.BasePath, base)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// normalizeBasePath cleans a base path: leading slash, no trailing slash,
+// no dot segments; "" and "/" become "". Returns false for values that are
+// not plain paths (query, fragment, backslashes, control or unsafe characters).
+func normalizeBasePath(p string) (string, bool) {
+	p = strings.TrimSpace(p)
+	if p == "" || p == "/" {
+		return "", true
+	}
+
+	for _, c := range p {
+		if !isPathChar(c) {
+			return "", false
+		}
+	}
+
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return "", true
+	}
+	return p, true
+}
+
+// isPathChar reports whether c may appear in a base path
+// (RFC 3986 unreserved, sub-delims except quotes, ":", "@", "%" and "/").
+func isPathChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("-._~!$&()*+,;=:@%/", c)
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// captureBasePath returns a handler that records the basePath from the context.
+func captureBasePath(got *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got, _ = r.Context().Value(ctxkeys.BasePath).(string)
+	})
+}
+
+// TestBasePathFromHeader tests prefix detection from trusted proxies
+func TestBasePathFromHeader(t *testing.T) {
+	mw := BasePathFromHeader(ForwardedPrefixConfig{
+		TrustedProxies: MustTrustedProxies("10.0.0.0/8"),
+		Fallback:       "/default",
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		prefix     string
+		expected   string
+	}{
+		{"trusted proxy", "10.0.0.1:1234", "/app", "/app"},
+		{"trailing slash", "10.0.0.1:1234", "/app/", "/app"},
+		{"missing leading slash", "10.0.0.1:1234", "tools/x", "/tools/x"},
+		{"dot segments", "10.0.0.1:1234", "/a/../b/./c", "/b/c"},
+		{"root", "10.0.0.1:1234", "/", ""},
+		{"proxy chain", "10.0.0.1:1234", "/outer, /inner", "/outer"},
+		{"no header", "10.0.0.1:1234", "", "/default"},
+		{"untrusted peer", "192.0.2.1:1234", "/evil", "/default"},
+		{"script injection", "10.0.0.1:1234", `/app"><script>`, "/default"},
+		{"query", "10.0.0.1:1234", "/app?x=1", "/default"},
+		{"backslash", "10.0.0.1:1234", `\evil.com`, "/default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.prefix != "" {
+				req.Header.Set("X-Forwarded-Prefix", tt.prefix)
+			}
+
+			var got string
+			mw(captureBasePath(&got)).ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.expected {
+				t.Errorf("expected basePath %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestBasePathFromHeader_CustomHeader tests a configurable header name
+func TestBasePathFromHeader_CustomHeader(t *testing.T) {
+	mw := BasePathFromHeader(ForwardedPrefixConfig{
+		TrustedProxies: MustTrustedProxies("127.0.0.1"),
+		Header:         "X-Script-Name",
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "127.0.0.1:5555"
+	req.Header.Set("X-Script-Name", "/mounted")
+	req.Header.Set("X-Forwarded-Prefix", "/ignored")
+
+	var got string
+	mw(captureBasePath(&got)).ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "/mounted" {
+		t.Errorf("expected basePath /mounted, got %q", got)
+	}
+}
+
+// TestStripBasePath tests prefix stripping for proxies that don't strip
+func TestStripBasePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		base         string
+		target       string
+		expectedCode int
+		expectedPath string
+		expectedRaw  string
+	}{
+		{"nested path", "/app", "/app/login", http.StatusOK, "/login", ""},
+		{"prefix only", "/app", "/app", http.StatusOK, "/", ""},
+		{"prefix with slash", "/app/", "/app/", http.StatusOK, "/", ""},
+		{"escaped path", "/app", "/app/files/a%2Fb", http.StatusOK, "/files/a/b", "/files/a%2Fb"},
+		{"escaped base", "/my%20app", "/my%20app/x", http.StatusOK, "/x", ""},
+		{"outside prefix", "/app", "/other/login", http.StatusNotFound, "", ""},
+		{"partial segment", "/app", "/apple", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotRaw, gotBase string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotRaw = r.URL.Path, r.URL.RawPath
+				gotBase, _ = r.Context().Value(ctxkeys.BasePath).(string)
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.target, nil)
+			StripBasePath(tt.base)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			if gotPath != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, gotPath)
+			}
+			if gotRaw != tt.expectedRaw {
+				t.Errorf("expected raw path %q, got %q", tt.expectedRaw, gotRaw)
+			}
+			if want, _ := normalizeBasePath(tt.base); gotBase != want {
+				t.Errorf("expected basePath %q, got %q", want, gotBase)
+			}
+			if req.URL.EscapedPath() != tt.target {
+				t.Error("original request should not be modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// CSPConfig describes a Content-Security-Policy.
+//
+// Each field maps to one directive. Empty fields are omitted from the policy.
+// Source values are written as-is, so keywords must be quoted:
+//
+//	ScriptSrc: []string{"'self'", "https://cdn.example.com"}
+//
+// The per-request nonce is always added to script-src (and to style-src if
+// StyleNonce is set). If ScriptSrc is empty, DefaultSrc is used as the base.
+type CSPConfig struct {
+	DefaultSrc     []string // default-src
+	ScriptSrc      []string // script-src (nonce is appended automatically)
+	StyleSrc       []string // style-src
+	ImgSrc         []string // img-src
+	FontSrc        []string // font-src
+	ConnectSrc     []string // connect-src
+	MediaSrc       []string // media-src
+	ObjectSrc      []string // object-src
+	FrameSrc       []string // frame-src
+	WorkerSrc      []string // worker-src
+	ManifestSrc    []string // manifest-src
+	BaseURI        []string // base-uri
+	FormAction     []string // form-action
+	FrameAncestors []string // frame-ancestors
+
+	// StyleNonce adds the nonce to style-src as well.
+	StyleNonce bool
+
+	// UpgradeInsecureRequests adds the upgrade-insecure-requests directive.
+	UpgradeInsecureRequests bool
+
+	// ReportURI adds a report-uri directive (e.g. the path of CSPReportHandler).
+	ReportURI string
+
+	// ReportOnly sends Content-Security-Policy-Report-Only instead of enforcing.
+	ReportOnly bool
+}
+
+// DefaultCSPConfig returns a strict same-origin policy.
+//
+// Policy:
+//
+//	default-src 'self'; script-src 'self' 'nonce-…'; object-src 'none';
+//	base-uri 'self'; form-action 'self'
+//
+// Note: The standard Alpine.js build evaluates expressions with new Function()
+// and needs 'unsafe-eval' in ScriptSrc (or use the Alpine CSP build).
+func DefaultCSPConfig() CSPConfig {
+	return CSPConfig{
+		DefaultSrc: []string{"'self'"},
+		ScriptSrc:  []string{"'self'"},
+		ObjectSrc:  []string{"'none'"},
+		BaseURI:    []string{"'self'"},
+		FormAction: []string{"'self'"},
+	}
+}
+
+// CSP is a middleware that sets a Content-Security-Policy header with a
+// per-request nonce.
+//
+// The nonce is stored in the request context (ctxkeys.CSPNonce). All scripts
+// rendered by hagg-lib (hxevents.RenderInitialEvents, hxevents.RenderToasts,
+// assets.Scripts) carry it automatically. For your own inline scripts use
+// CSPNonce(req).
+//
+// The header is added, not set: a frame-ancestors policy from
+// SecureWithConfig stays in place, and browsers enforce both.
+//
+// Example:
+//
+//	cfg := libmw.DefaultCSPConfig()
+//	cfg.ReportURI = "/csp-report"
+//	r.Use(libmw.CSP(cfg))
+//	r.Post("/csp-report", libmw.CSPReportHandler(slog.Default()).ServeHTTP)
+func CSP(cfg CSPConfig) func(http.Handler) http.Handler {
+	header := "Content-Security-Policy"
+	if cfg.ReportOnly {
+		header = "Content-Security-Policy-Report-Only"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := newNonce()
+			w.Header().Add(header, cfg.Policy(nonce))
+
+			ctx := context.WithValue(r.Context(), ctxkeys.CSPNonce, nonce)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Policy builds the policy string for the given nonce.
+// An empty nonce builds the policy without nonce sources.
+func (cfg CSPConfig) Policy(nonce string) string {
+	var nonceSrc []string
+	if nonce != "" {
+		nonceSrc = []string{"'nonce-" + nonce + "'"}
+	}
+
+	scriptSrc := cfg.ScriptSrc
+	if len(scriptSrc) == 0 {
+		scriptSrc = cfg.DefaultSrc
+	}
+	scriptSrc = append(append([]string(nil), scriptSrc...), nonceSrc...)
+
+	styleSrc := cfg.StyleSrc
+	if cfg.StyleNonce {
+		if len(styleSrc) == 0 {
+			styleSrc = cfg.DefaultSrc
+		}
+		styleSrc = append(append([]string(nil), styleSrc...), nonceSrc...)
+	}
+
+	directives := []struct {
+		name    string
+		sources []string
+	}{
+		{"default-src", cfg.DefaultSrc},
+		{"script-src", scriptSrc},
+		{"style-src", styleSrc},
+		{"img-src", cfg.ImgSrc},
+		{"font-src", cfg.FontSrc},
+		{"connect-src", cfg.ConnectSrc},
+		{"media-src", cfg.MediaSrc},
+		{"object-src", cfg.ObjectSrc},
+		{"frame-src", cfg.FrameSrc},
+		{"worker-src", cfg.WorkerSrc},
+		{"manifest-src", cfg.ManifestSrc},
+		{"base-uri", cfg.BaseURI},
+		{"form-action", cfg.FormAction},
+		{"frame-ancestors", cfg.FrameAncestors},
+	}
+
+	var parts []string
+	for _, d := range directives {
+		if len(d.sources) == 0 {
+			continue
+		}
+		parts = append(parts, d.name+" "+strings.Join(d.sources, " "))
+	}
+
+	if cfg.UpgradeInsecureRequests {
+		parts = append(parts, "upgrade-insecure-requests")
+	}
+	if cfg.ReportURI != "" {
+		parts = append(parts, "report-uri "+cfg.ReportURI)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// CSPNonce returns the CSP nonce of the current request.
+// Returns an empty string if the CSP middleware is not used.
+//
+// Example:
+//
+//	h.Script(g.Attr("nonce", libmw.CSPNonce(req)), g.Raw(`init()`))
+func CSPNonce(r *http.Request) string {
+	nonce, _ := r.Context().Value(ctxkeys.CSPNonce).(string)
+	return nonce
+}
+
+// newNonce returns 128 bits of randomness, base64-encoded.
+func newNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// maxCSPReportSize limits the body size accepted by CSPReportHandler.
+const maxCSPReportSize = 64 << 10
+
+// CSPReportHandler returns a handler that logs CSP violation reports via slog.
+//
+// Supports both the legacy report-uri format (application/csp-report) and the
+// Reporting API format (application/reports+json). Bodies over 64 KiB and
+// malformed reports are dropped. Always responds with 204.
+//
+// Example:
+//
+//	r.Post("/csp-report", libmw.CSPReportHandler(logger).ServeHTTP)
+func CSPReportHandler(logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCSPReportSize+1))
+		if err != nil {
+			logger.Warn("csp report: read body", "error", err)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if len(body) > maxCSPReportSize {
+			logger.Warn("csp report: body too large", "limit", maxCSPReportSize)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		for _, report := range parseCSPReports(body) {
+			logger.Warn("csp violation",
+				"document", firstString(report, "document-uri", "documentURL"),
+				"directive", firstString(report, "effective-directive", "effectiveDirective", "violated-directive"),
+				"blocked", firstString(report, "blocked-uri", "blockedURL"),
+				"source", firstString(report, "source-file", "sourceFile"),
+				"line", report["line-number"],
+				"disposition", firstString(report, "disposition"),
+				"user_agent", r.UserAgent(),
+			)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// parseCSPReports extracts report bodies from both supported formats.
+// Invalid payloads yield no reports.
+func parseCSPReports(body []byte) []map[string]any {
+	// Legacy: {"csp-report": {...}}
+	var legacy struct {
+		Report map[string]any `json:"csp-report"`
+	}
+	if err := json.Unmarshal(body, &legacy); err == nil && legacy.Report != nil {
+		return []map[string]any{legacy.Report}
+	}
+
+	// Reporting API: [{"type": "csp-violation", "body": {...}}, ...]
+	var batch []struct {
+		Type string         `json:"type"`
+		Body map[string]any `json:"body"`
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil
+	}
+
+	var reports []map[string]any
+	for _, entry := range batch {
+		if entry.Type == "csp-violation" && entry.Body != nil {
+			reports = append(reports, entry.Body)
+		}
+	}
+	return reports
+}
+
+// firstString returns the first non-empty string value among the given keys.
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
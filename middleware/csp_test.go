@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCSP tests the policy header and the nonce in the request context
+func TestCSP(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    CSPConfig
+		header string
+	}{
+		{"enforced", DefaultCSPConfig(), "Content-Security-Policy"},
+		{"report only", CSPConfig{DefaultSrc: []string{"'self'"}, ReportOnly: true}, "Content-Security-Policy-Report-Only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nonce string
+			h := CSP(tt.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = CSPNonce(r)
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if nonce == "" {
+				t.Fatal("expected nonce in request context")
+			}
+			policy := rec.Header().Get(tt.header)
+			if !strings.Contains(policy, "script-src 'self' 'nonce-"+nonce+"'") {
+				t.Errorf("expected nonce in script-src, got %q", policy)
+			}
+			other := "Content-Security-Policy-Report-Only"
+			if tt.cfg.ReportOnly {
+				other = "Content-Security-Policy"
+			}
+			if rec.Header().Get(other) != "" {
+				t.Errorf("%s should not be set", other)
+			}
+		})
+	}
+}
+
+// TestCSP_WithSecure tests that CSP keeps the frame-ancestors policy of
+// SecureWithConfig, in both middleware orders
+func TestCSP_WithSecure(t *testing.T) {
+	secure := SecureWithConfig(SecureConfig{FrameAncestors: []string{"https://portal.example.com"}})
+	csp := CSP(DefaultCSPConfig())
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+	}{
+		{"secure then csp", secure(csp(okHandler))},
+		{"csp then secure", csp(secure(okHandler))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			values := rec.Header().Values("Content-Security-Policy")
+			if len(values) != 2 {
+				t.Fatalf("expected two policies, got %q", values)
+			}
+			joined := strings.Join(values, "\n")
+			if !strings.Contains(joined, "frame-ancestors https://portal.example.com") {
+				t.Errorf("expected frame-ancestors policy, got %q", values)
+			}
+			if !strings.Contains(joined, "script-src 'self' 'nonce-") {
+				t.Errorf("expected nonce policy, got %q", values)
+			}
+		})
+	}
+}
+
+// TestCSP_FreshNonce tests that every request gets a new nonce
+func TestCSP_FreshNonce(t *testing.T) {
+	seen := map[string]bool{}
+	h := CSP(DefaultCSPConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen[CSPNonce(r)] = true
+	}))
+
+	for range 10 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected 10 distinct nonces, got %d", len(seen))
+	}
+}
+
+// TestCSPConfig_Policy tests the directive rendering
+func TestCSPConfig_Policy(t *testing.T) {
+	cfg := CSPConfig{
+		DefaultSrc:              []string{"'self'"},
+		ImgSrc:                  []string{"'self'", "data:"},
+		StyleNonce:              true,
+		UpgradeInsecureRequests: true,
+		ReportURI:               "/csp-report",
+	}
+
+	tests := []struct {
+		name     string
+		nonce    string
+		expected string
+	}{
+		{
+			"with nonce", "abc",
+			"default-src 'self'; script-src 'self' 'nonce-abc'; style-src 'self' 'nonce-abc'; img-src 'self' data:; upgrade-insecure-requests; report-uri /csp-report",
+		},
+		{
+			"without nonce", "",
+			"default-src 'self'; script-src 'self'; style-src 'self'; img-src 'self' data:; upgrade-insecure-requests; report-uri /csp-report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.Policy(tt.nonce); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestCSPReportHandler tests parsing of both report formats and rejection
+// of invalid bodies
+func TestCSPReportHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    []string // blocked URIs of logged violations
+	}{
+		{
+			"legacy report", "application/csp-report",
+			`{"csp-report":{"document-uri":"https://example.com/","violated-directive":"script-src","blocked-uri":"https://evil.com/x.js"}}`,
+			[]string{"https://evil.com/x.js"},
+		},
+		{
+			"reporting api", "application/reports+json",
+			`[{"type":"csp-violation","body":{"documentURL":"https://example.com/","effectiveDirective":"img-src","blockedURL":"https://a.com/1.png"}},` +
+				`{"type":"deprecation","body":{"id":"x"}},` +
+				`{"type":"csp-violation","body":{"blockedURL":"https://b.com/2.png"}}]`,
+			[]string{"https://a.com/1.png", "https://b.com/2.png"},
+		},
+		{"malformed", "application/csp-report", `{"csp-report":`, nil},
+		{"wrong shape", "application/reports+json", `{"type":"csp-violation"}`, nil},
+		{
+			"oversized", "application/csp-report",
+			`{"csp-report":{"blocked-uri":"https://evil.com/x.js","padding":"` + strings.Repeat("x", maxCSPReportSize) + `"}}`,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+			req := httptest.NewRequest("POST", "/csp-report", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			CSPReportHandler(logger).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("expected status 204, got %d", rec.Code)
+			}
+			if got := strings.Count(logs.String(), "csp violation"); got != len(tt.expected) {
+				t.Errorf("expected %d violations, got %d in %q", len(tt.expected), got, logs.String())
+			}
+			for _, blocked := range tt.expected {
+				if !strings.Contains(logs.String(), "blocked="+blocked) {
+					t.Errorf("expected blocked=%s in %q", blocked, logs.String())
+				}
+			}
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		CSPReportHandler(slog.Default()).ServeHTTP(rec, httptest.NewRequest("GET", "/csp-report", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected status 405, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// OriginConfig configures the Origin middleware.
+type OriginConfig struct {
+	// PublicOrigin is the canonical origin of the app (e.g. "https://example.com").
+	// If set, it is used for every request and forwarded headers are ignored.
+	PublicOrigin string
+
+	// AllowedHosts lists the hosts the origin may be derived from when no
+	// PublicOrigin is set (e.g. "example.com", "example.com:8443"). An entry
+	// without a port matches any port. Requests for other hosts are rejected
+	// with 400, so a forged Host header never ends up in absolute URLs.
+	AllowedHosts []string
+
+	// TrustedProxies whose X-Forwarded-Proto and X-Forwarded-Host are honored.
+	TrustedProxies *TrustedProxies
+}
+
+// Origin is a middleware that resolves the public origin (scheme://host) of
+// the request and stores it under ctxkeys.Origin for view.AbsoluteURL.
+//
+// Resolution order:
+//  1. cfg.PublicOrigin
+//  2. X-Forwarded-Proto / X-Forwarded-Host from cfg.TrustedProxies
+//  3. The request itself (r.TLS, r.Host)
+//
+// The Host header is client-controlled, so a derived host (2 and 3) must be
+// listed in cfg.AllowedHosts; otherwise the request is rejected with 400.
+//
+// Panics if PublicOrigin is not a valid absolute http(s) origin, or if
+// neither PublicOrigin nor AllowedHosts is set.
+//
+// Example:
+//
+//	r.Use(middleware.Origin(middleware.OriginConfig{
+//	    AllowedHosts:   []string{"example.com"},
+//	    TrustedProxies: middleware.MustTrustedProxies("10.0.0.0/8"),
+//	}))
+//
+//	// In handlers (with BasePath "/app")
+//	view.AbsoluteURL(req, "/confirm")  // "https://example.com/app/confirm"
+func Origin(cfg OriginConfig) func(http.Handler) http.Handler {
+	public := ""
+	if cfg.PublicOrigin != "" {
+		u, err := url.Parse(cfg.PublicOrigin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			panic("middleware: invalid public origin " + cfg.PublicOrigin)
+		}
+		public = u.Scheme + "://" + u.Host
+	} else if len(cfg.AllowedHosts) == 0 {
+		panic("middleware: Origin requires PublicOrigin or AllowedHosts")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := public
+			if origin == "" {
+				scheme, host := requestOrigin(r, cfg.TrustedProxies)
+				if !isAllowedHost(host, cfg.AllowedHosts) {
+					http.Error(w, "Bad Request", http.StatusBadRequest)
+					return
+				}
+				origin = scheme + "://" + host
+			}
+
+			ctx := context.WithValue(r.Context(), ctxkeys.Origin, origin)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestOrigin derives scheme and host from the request, honoring forwarded
+// headers of trusted proxies. Invalid forwarded values are ignored.
+func requestOrigin(r *http.Request, proxies *TrustedProxies) (scheme, host string) {
+	scheme = "http"
+	if isHTTPS(r, proxies) {
+		scheme = "https"
+	}
+
+	host = r.Host
+	if fh := proxies.Header(r, "X-Forwarded-Host"); fh != "" && isValidHost(fh) {
+		host = fh
+	}
+
+	return scheme, host
+}
+
+// isAllowedHost reports whether host matches an entry of allowed, either
+// exactly or by hostname for entries without a port (case-insensitive).
+func isAllowedHost(host string, allowed []string) bool {
+	if !isValidHost(host) {
+		return false
+	}
+	u, _ := url.Parse("http://" + host)
+	for _, a := range allowed {
+		if strings.EqualFold(a, host) || strings.EqualFold(a, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidHost reports whether h is a plain host[:port] (no userinfo, path or spaces).
+func isValidHost(h string) bool {
+	u, err := url.Parse("http://" + h)
+	return err == nil && u.Host == h && u.User == nil && u.Hostname() != ""
+}
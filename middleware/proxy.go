@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies is a set of networks whose forwarded headers
+// (X-Forwarded-Proto, X-Forwarded-Prefix, ...) are trusted.
+//
+// The check is based on r.RemoteAddr. Do not combine it with middleware that
+// rewrites RemoteAddr from client-controlled headers (e.g. chimw.RealIP)
+// earlier in the chain.
+//
+// A nil *TrustedProxies trusts nobody.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// NewTrustedProxies parses CIDRs (e.g. "10.0.0.0/8") or single addresses
+// (e.g. "127.0.0.1", "::1") into a TrustedProxies set.
+//
+// Example:
+//
+//	proxies, err := middleware.NewTrustedProxies("127.0.0.1", "10.0.0.0/8")
+func NewTrustedProxies(cidrs ...string) (*TrustedProxies, error) {
+	t := &TrustedProxies{}
+	for _, c := range cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+
+		if strings.Contains(c, "/") {
+			p, err := netip.ParsePrefix(c)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", c, err)
+			}
+			t.prefixes = append(t.prefixes, p.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(c)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", c, err)
+		}
+		addr = addr.Unmap()
+		t.prefixes = append(t.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return t, nil
+}
+
+// MustTrustedProxies is like NewTrustedProxies but panics on invalid input.
+// Intended for static configuration at startup.
+func MustTrustedProxies(cidrs ...string) *TrustedProxies {
+	t, err := NewTrustedProxies(cidrs...)
+	if err != nil {
+		panic("middleware: " + err.Error())
+	}
+	return t
+}
+
+// Trusts reports whether the request comes directly from a trusted proxy.
+func (t *TrustedProxies) Trusts(r *http.Request) bool {
+	if t == nil || len(t.prefixes) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range t.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Header returns the first value of a forwarded header if the request comes
+// from a trusted proxy, otherwise an empty string.
+//
+// Comma-separated values (added by proxy chains) yield the first,
+// client-facing entry:
+//
+//	X-Forwarded-Proto: https, http  ->  "https"
+func (t *TrustedProxies) Header(r *http.Request, name string) string {
+	if !t.Trusts(r) {
+		return ""
+	}
+	v, _, _ := strings.Cut(r.Header.Get(name), ",")
+	return strings.TrimSpace(v)
+}
+
+// isHTTPS reports whether the client connection is HTTPS, either directly
+// (r.TLS) or via X-Forwarded-Proto from a trusted proxy.
+func isHTTPS(r *http.Request, proxies *TrustedProxies) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return strings.EqualFold(proxies.Header(r, "X-Forwarded-Proto"), "https")
+}
@@ -1,6 +1,13 @@
 package middleware
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Secure is a middleware that sets common security headers.
 // It is recommended to use this middleware in all production deployments.
@@ -25,15 +32,232 @@ import "net/http"
 //	r.Use(libmw.Secure)            // recommended
 //
 // Note: These headers are safe defaults. X-Frame-Options: DENY prevents
-// embedding in iframes - if you need iframe support, consider a custom
-// implementation with SAMEORIGIN or CSP frame-ancestors.
+// embedding in iframes - if you need iframe support, use SecureWithConfig
+// with SAMEORIGIN or FrameAncestors.
 func Secure(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("X-Frame-Options", "DENY")
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	return SecureWithConfig(DefaultSecureConfig())(next)
+}
+
+// SecureConfig configures the headers set by SecureWithConfig.
+// Empty fields are omitted.
+type SecureConfig struct {
+	// FrameOptions sets X-Frame-Options ("DENY" or "SAMEORIGIN").
+	// Leave empty when using FrameAncestors with external origins.
+	FrameOptions string
+
+	// FrameAncestors sets a "Content-Security-Policy: frame-ancestors ..." header.
+	// Sources are written as-is, e.g. []string{"'self'", "https://portal.intranet"}.
+	// The header is added separately, so it combines with middleware.CSP
+	// in either order.
+	FrameAncestors []string
+
+	// ContentTypeNosniff sets X-Content-Type-Options: nosniff.
+	ContentTypeNosniff bool
+
+	// XSSProtection sets the legacy X-XSS-Protection header.
+	// Modern browsers ignore it; "0" explicitly disables the filter.
+	XSSProtection string
+
+	// ReferrerPolicy sets Referrer-Policy.
+	ReferrerPolicy string
+
+	// HSTSMaxAge enables Strict-Transport-Security when > 0.
+	// Only sent on HTTPS requests (r.TLS or X-Forwarded-Proto from TrustedProxies).
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+
+	// TrustedProxies allows X-Forwarded-Proto to mark a request as HTTPS.
+	TrustedProxies *TrustedProxies
+
+	// PermissionsPolicy sets Permissions-Policy. Keys are features, values
+	// the allowlist (empty = disabled for everyone):
+	//
+	//	map[string][]string{"camera": nil, "geolocation": {"self"}}
+	//	// Permissions-Policy: camera=(), geolocation=(self)
+	PermissionsPolicy map[string][]string
+
+	// Cross-origin isolation headers.
+	CrossOriginOpenerPolicy   string // Cross-Origin-Opener-Policy (e.g. "same-origin")
+	CrossOriginEmbedderPolicy string // Cross-Origin-Embedder-Policy (e.g. "require-corp")
+	CrossOriginResourcePolicy string // Cross-Origin-Resource-Policy (e.g. "same-origin")
+}
 
-		next.ServeHTTP(w, r)
+// DefaultSecureConfig returns the configuration used by Secure.
+func DefaultSecureConfig() SecureConfig {
+	return SecureConfig{
+		FrameOptions:       "DENY",
+		ContentTypeNosniff: true,
+		XSSProtection:      "1; mode=block",
+		ReferrerPolicy:     "strict-origin-when-cross-origin",
+	}
+}
+
+// secureConfigKey stores the active SecureConfig for SecureOverride.
+type secureConfigKey struct{}
+
+// SecureWithConfig is a middleware that sets security headers from cfg.
+//
+// Example (page embedded in an intranet portal):
+//
+//	cfg := libmw.DefaultSecureConfig()
+//	cfg.FrameOptions = ""  // X-Frame-Options cannot express an allowlist
+//	cfg.FrameAncestors = []string{"'self'", "https://portal.intranet"}
+//	cfg.HSTSMaxAge = 365 * 24 * time.Hour
+//	cfg.TrustedProxies = libmw.MustTrustedProxies("10.0.0.0/8")
+//	r.Use(libmw.SecureWithConfig(cfg))
+func SecureWithConfig(cfg SecureConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cfg.apply(w.Header(), r)
+
+			ctx := context.WithValue(r.Context(), secureConfigKey{}, cfg)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SecureOverride is a per-route middleware that adjusts the configuration of
+// an outer SecureWithConfig (or Secure) and rewrites the headers.
+//
+// Without an outer Secure middleware, fn modifies DefaultSecureConfig().
+//
+// Example:
+//
+//	r.With(libmw.SecureOverride(func(c *libmw.SecureConfig) {
+//	    c.FrameOptions = "SAMEORIGIN"
+//	})).Get("/embed", wrapper.Wrap(pages.Embed))
+func SecureOverride(fn func(*SecureConfig)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cfg, ok := r.Context().Value(secureConfigKey{}).(SecureConfig)
+			if !ok {
+				cfg = DefaultSecureConfig()
+			}
+
+			// Copy reference fields so fn cannot mutate the outer config
+			cfg.FrameAncestors = slices.Clone(cfg.FrameAncestors)
+			if cfg.PermissionsPolicy != nil {
+				pp := make(map[string][]string, len(cfg.PermissionsPolicy))
+				for k, v := range cfg.PermissionsPolicy {
+					pp[k] = slices.Clone(v)
+				}
+				cfg.PermissionsPolicy = pp
+			}
+			fn(&cfg)
+
+			clearSecureHeaders(w.Header())
+			cfg.apply(w.Header(), r)
+
+			ctx := context.WithValue(r.Context(), secureConfigKey{}, cfg)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// secureHeaders are the headers managed by SecureConfig
+// (Content-Security-Policy is handled separately).
+var secureHeaders = []string{
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"X-XSS-Protection",
+	"Referrer-Policy",
+	"Strict-Transport-Security",
+	"Permissions-Policy",
+	"Cross-Origin-Opener-Policy",
+	"Cross-Origin-Embedder-Policy",
+	"Cross-Origin-Resource-Policy",
+}
+
+// frameAncestorsPrefix identifies the CSP header value added for FrameAncestors.
+const frameAncestorsPrefix = "frame-ancestors "
+
+// clearSecureHeaders removes all headers previously set by SecureConfig.apply.
+// Other Content-Security-Policy values (e.g. from middleware.CSP) are kept.
+func clearSecureHeaders(hdr http.Header) {
+	for _, name := range secureHeaders {
+		hdr.Del(name)
+	}
+
+	csp := slices.DeleteFunc(hdr.Values("Content-Security-Policy"), func(v string) bool {
+		return strings.HasPrefix(v, frameAncestorsPrefix)
 	})
+	hdr.Del("Content-Security-Policy")
+	for _, v := range csp {
+		hdr.Add("Content-Security-Policy", v)
+	}
+}
+
+// apply writes the configured headers.
+func (cfg SecureConfig) apply(hdr http.Header, r *http.Request) {
+	setIf := func(name, value string) {
+		if value != "" {
+			hdr.Set(name, value)
+		}
+	}
+
+	setIf("X-Frame-Options", cfg.FrameOptions)
+	if cfg.ContentTypeNosniff {
+		hdr.Set("X-Content-Type-Options", "nosniff")
+	}
+	setIf("X-XSS-Protection", cfg.XSSProtection)
+	setIf("Referrer-Policy", cfg.ReferrerPolicy)
+	setIf("Permissions-Policy", cfg.permissionsPolicy())
+	setIf("Cross-Origin-Opener-Policy", cfg.CrossOriginOpenerPolicy)
+	setIf("Cross-Origin-Embedder-Policy", cfg.CrossOriginEmbedderPolicy)
+	setIf("Cross-Origin-Resource-Policy", cfg.CrossOriginResourcePolicy)
+
+	if len(cfg.FrameAncestors) > 0 {
+		hdr.Add("Content-Security-Policy", frameAncestorsPrefix+strings.Join(cfg.FrameAncestors, " "))
+	}
+
+	if cfg.HSTSMaxAge > 0 && isHTTPS(r, cfg.TrustedProxies) {
+		hdr.Set("Strict-Transport-Security", cfg.hsts())
+	}
+}
+
+// hsts builds the Strict-Transport-Security value.
+func (cfg SecureConfig) hsts() string {
+	v := "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge/time.Second), 10)
+	if cfg.HSTSIncludeSubdomains {
+		v += "; includeSubDomains"
+	}
+	if cfg.HSTSPreload {
+		v += "; preload"
+	}
+	return v
+}
+
+// permissionsPolicy builds the Permissions-Policy value with sorted features.
+// Keywords (self, *) are written bare, origins are quoted.
+func (cfg SecureConfig) permissionsPolicy() string {
+	if len(cfg.PermissionsPolicy) == 0 {
+		return ""
+	}
+
+	features := make([]string, 0, len(cfg.PermissionsPolicy))
+	for f := range cfg.PermissionsPolicy {
+		features = append(features, f)
+	}
+	slices.Sort(features)
+
+	parts := make([]string, 0, len(features))
+	for _, f := range features {
+		origins := cfg.PermissionsPolicy[f]
+		if slices.Contains(origins, "*") {
+			parts = append(parts, f+"=*")
+			continue
+		}
+
+		allow := make([]string, 0, len(origins))
+		for _, o := range origins {
+			if o == "self" || o == "src" || strings.HasPrefix(o, `"`) {
+				allow = append(allow, o)
+			} else {
+				allow = append(allow, strconv.Quote(o))
+			}
+		}
+		parts = append(parts, f+"=("+strings.Join(allow, " ")+")")
+	}
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestSecure tests the default headers
+func TestSecure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Secure(okHandler).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	expected := map[string]string{
+		"X-Frame-Options":        "DENY",
+		"X-Content-Type-Options": "nosniff",
+		"X-XSS-Protection":       "1; mode=block",
+		"Referrer-Policy":        "strict-origin-when-cross-origin",
+	}
+	for name, value := range expected {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("expected %s %q, got %q", name, value, got)
+		}
+	}
+	if rec.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS should not be set by default")
+	}
+}
+
+// TestSecureWithConfig_HSTS tests HSTS only on HTTPS or trusted forwarded proto
+func TestSecureWithConfig_HSTS(t *testing.T) {
+	cfg := SecureConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           true,
+		TrustedProxies:        MustTrustedProxies("10.0.0.0/8"),
+	}
+	const want = "max-age=31536000; includeSubDomains; preload"
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		proto      string
+		expected   string
+	}{
+		{"plain http", "192.0.2.1:1234", "", ""},
+		{"trusted proxy https", "10.1.2.3:1234", "https", want},
+		{"trusted proxy chain", "10.1.2.3:1234", "https, http", want},
+		{"untrusted proxy https", "192.0.2.1:1234", "https", ""},
+		{"trusted proxy http", "10.1.2.3:1234", "http", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+
+			rec := httptest.NewRecorder()
+			SecureWithConfig(cfg)(okHandler).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Strict-Transport-Security"); got != tt.expected {
+				t.Errorf("expected HSTS %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("direct TLS", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "https://example.com/", nil)
+		rec := httptest.NewRecorder()
+		SecureWithConfig(cfg)(okHandler).ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Strict-Transport-Security"); got != want {
+			t.Errorf("expected HSTS %q, got %q", want, got)
+		}
+	})
+}
+
+// TestSecureWithConfig_Policies tests frame-ancestors, Permissions-Policy and COOP/COEP/CORP
+func TestSecureWithConfig_Policies(t *testing.T) {
+	cfg := SecureConfig{
+		FrameAncestors: []string{"'self'", "https://portal.intranet"},
+		PermissionsPolicy: map[string][]string{
+			"geolocation": {"self", "https://maps.example.com"},
+			"camera":      nil,
+			"fullscreen":  {"*"},
+		},
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginEmbedderPolicy: "require-corp",
+		CrossOriginResourcePolicy: "same-site",
+	}
+
+	rec := httptest.NewRecorder()
+	SecureWithConfig(cfg)(okHandler).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	expected := map[string]string{
+		"Content-Security-Policy":      "frame-ancestors 'self' https://portal.intranet",
+		"Permissions-Policy":           `camera=(), fullscreen=*, geolocation=(self "https://maps.example.com")`,
+		"Cross-Origin-Opener-Policy":   "same-origin",
+		"Cross-Origin-Embedder-Policy": "require-corp",
+		"Cross-Origin-Resource-Policy": "same-site",
+		"X-Frame-Options":              "",
+	}
+	for name, value := range expected {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("expected %s %q, got %q", name, value, got)
+		}
+	}
+}
+
+// TestSecureOverride tests per-route overrides
+func TestSecureOverride(t *testing.T) {
+	cfg := DefaultSecureConfig()
+	cfg.FrameAncestors = []string{"'none'"}
+
+	override := SecureOverride(func(c *SecureConfig) {
+		c.FrameOptions = "SAMEORIGIN"
+		c.FrameAncestors = []string{"'self'"}
+	})
+
+	// Simulate a CSP header set by another middleware
+	other := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Content-Security-Policy", "default-src 'self'")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler := other(SecureWithConfig(cfg)(override(okHandler)))
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/embed", nil))
+
+	if got := rec.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+		t.Errorf("expected X-Frame-Options SAMEORIGIN, got %q", got)
+	}
+
+	csp := rec.Header().Values("Content-Security-Policy")
+	if len(csp) != 2 || csp[0] != "default-src 'self'" || csp[1] != "frame-ancestors 'self'" {
+		t.Errorf("unexpected Content-Security-Policy values %q", csp)
+	}
+
+	if cfg.FrameAncestors[0] != "'none'" {
+		t.Error("override should not mutate the outer config")
+	}
+}
+
+// TestTrustedProxies tests proxy address matching
+func TestTrustedProxies(t *testing.T) {
+	proxies := MustTrustedProxies("127.0.0.1", "10.0.0.0/8", "::1")
+
+	tests := []struct {
+		remoteAddr string
+		expected   bool
+	}{
+		{"127.0.0.1:8080", true},
+		{"10.20.30.40:1234", true},
+		{"[::1]:8080", true},
+		{"[::ffff:10.0.0.1]:8080", true},
+		{"192.0.2.1:1234", false},
+		{"garbage", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if got := proxies.Trusts(req); got != tt.expected {
+			t.Errorf("Trusts(%q) = %v, expected %v", tt.remoteAddr, got, tt.expected)
+		}
+	}
+
+	var none *TrustedProxies
+	if none.Trusts(httptest.NewRequest("GET", "/", nil)) {
+		t.Error("nil TrustedProxies should trust nobody")
+	}
+
+	if _, err := NewTrustedProxies("not-an-ip"); err == nil {
+		t.Error("expected error for invalid proxy address")
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/handler"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// minSigningKeyLen is the minimum key length for URLSigning (256 bit).
+const minSigningKeyLen = 32
+
+// URLSigning is a middleware that provides the HMAC key for signed URLs
+// (view.SignedURL) and their verification (RequireSignedURL).
+//
+// Panics if key is shorter than 32 bytes. Load the key from configuration
+// and keep it stable - rotating it invalidates all outstanding links.
+// view.SignedURL panics on requests that did not pass this middleware
+// (view.SignedURLErr returns view.ErrNoSigningKey).
+//
+// Example:
+//
+//	r.Use(middleware.URLSigning(cfg.URLKey))
+func URLSigning(key []byte) func(http.Handler) http.Handler {
+	if len(key) < minSigningKeyLen {
+		panic("middleware: URL signing key must be at least 32 bytes")
+	}
+	key = append([]byte(nil), key...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ctxkeys.SigningKey, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireSignedURL is a middleware that only lets requests with a valid,
+// unexpired signature (see view.SignedURL) through.
+//
+// Rejected requests are passed to onError as *handler.Error:
+//   - Expired links: 410 Gone
+//   - Missing or tampered signatures: 403 Forbidden
+//
+// onError defaults to handler.WriteError; pass wrapper.Error to use the
+// app's error page and logging.
+//
+// Example:
+//
+//	r.With(middleware.RequireSignedURL(wrapper.Error)).
+//	    Get("/downloads/{file}", wrapper.Wrap(downloads.Get))
+func RequireSignedURL(onError func(http.ResponseWriter, *http.Request, error)) func(http.Handler) http.Handler {
+	if onError == nil {
+		onError = handler.WriteError
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := view.VerifySignedURL(r)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r)
+			case errors.Is(err, view.ErrSignatureExpired):
+				onError(w, r, handler.WrapError(http.StatusGone, "This link has expired.", err))
+			default:
+				onError(w, r, handler.WrapError(http.StatusForbidden, "This link is invalid.", err))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package pagination
+
+import (
+	"strconv"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/hx"
+)
+
+// Labels of the navigation. Override them for other languages.
+var (
+	PrevLabel = "‹ Previous"
+	NextLabel = "Next ›"
+	GapLabel  = "…"
+)
+
+// Nav renders page navigation for p:
+//
+//	<nav class="pagination" aria-label="Pagination">
+//	  <a href="/app/users?page=4&size=20" rel="prev">‹ Previous</a>
+//	  <a href="/app/users?page=1&size=20">1</a>
+//	  <span class="gap">…</span>
+//	  <a href="/app/users?page=5&size=20" aria-current="page">5</a>
+//	  ...
+//	</nav>
+//
+// Disabled prev/next links are rendered as <span aria-disabled="true">.
+// Nothing is rendered if there is only one page.
+func Nav(p Page) g.Node {
+	if p.TotalPages() <= 1 {
+		return nil
+	}
+
+	items := []g.Node{navLink(p.PrevURL(), "prev", PrevLabel)}
+	for _, n := range p.Pages() {
+		switch {
+		case n == 0:
+			items = append(items, h.Span(h.Class("gap"), g.Text(GapLabel)))
+		case n == p.Number:
+			items = append(items, h.A(h.Href(p.URL(n)), h.Aria("current", "page"), g.Text(strconv.Itoa(n))))
+		default:
+			items = append(items, h.A(h.Href(p.URL(n)), g.Text(strconv.Itoa(n))))
+		}
+	}
+	items = append(items, navLink(p.NextURL(), "next", NextLabel))
+
+	return h.Nav(h.Class("pagination"), h.Aria("label", "Pagination"), g.Group(items))
+}
+
+// SimpleNav renders only prev/next links (e.g. for cursor pagination
+// or lists without a total).
+func SimpleNav(prevURL, nextURL string) g.Node {
+	if prevURL == "" && nextURL == "" {
+		return nil
+	}
+	return h.Nav(
+		h.Class("pagination"),
+		h.Aria("label", "Pagination"),
+		navLink(prevURL, "prev", PrevLabel),
+		navLink(nextURL, "next", NextLabel),
+	)
+}
+
+// navLink renders a prev/next link, or a disabled span if href is empty.
+func navLink(href, rel, label string) g.Node {
+	if href == "" {
+		return h.Span(h.Class(rel), h.Aria("disabled", "true"), g.Text(label))
+	}
+	return h.A(h.Class(rel), h.Href(href), h.Rel(rel), g.Text(label))
+}
+
+// InfiniteScroll renders items with row and passes the infinite-scroll
+// attributes to the last row (nil for all other rows):
+//
+//	hx-get="<nextURL>" hx-trigger="revealed" hx-swap="afterend"
+//
+// When the last row scrolls into view, htmx requests the next page and
+// inserts the returned rows after it; the new last row carries the
+// attributes for the page after that. Pass nextURL = "" on the last page.
+func InfiniteScroll[T any](items []T, nextURL string, row func(item T, attrs g.Node) g.Node) g.Node {
+	nodes := make([]g.Node, len(items))
+	for i, item := range items {
+		var attrs g.Node
+		if i == len(items)-1 && nextURL != "" {
+			attrs = ScrollAttrs(nextURL)
+		}
+		nodes[i] = row(item, attrs)
+	}
+	return g.Group(nodes)
+}
+
+// ScrollAttrs returns the infinite-scroll attributes for a custom last row.
+// nextURL must already be basePath-aware (see Page.NextURL, Cursor.NextURL).
+func ScrollAttrs(nextURL string) g.Node {
+	return g.Group([]g.Node{
+		g.Attr("hx-get", nextURL),
+		hx.Trigger(hx.Revealed()),
+		hx.Swap(hx.AfterEnd),
+	})
+}
@@ -0,0 +1,312 @@
+// Package pagination provides offset and cursor pagination for list screens.
+//
+// Page number, size and cursor are read from query parameters; links are
+// basePath-aware and keep all other query parameters (filters, sorting).
+//
+// # Usage Example
+//
+//	func List(ctx *handler.Context) error {
+//	    total, _ := repo.CountUsers(ctx.Req.Context())
+//	    p := pagination.New(ctx.Req, total)  // ?page=3&size=20
+//
+//	    users, _ := repo.ListUsers(ctx.Req.Context(), p.Offset(), p.Limit())
+//	    return ctx.Render(h.Div(
+//	        usersTable(users),
+//	        pagination.Nav(p),  // ‹ Previous 1 … 2 3 4 … 10 Next ›
+//	    ))
+//	}
+//
+// # Infinite Scroll
+//
+// InfiniteScroll adds hx-get with hx-trigger="revealed" to the last row; when
+// it scrolls into view, htmx loads the next page and inserts it after the row:
+//
+//	h.TBody(pagination.InfiniteScroll(users, p.NextURL(),
+//	    func(u User, attrs g.Node) g.Node {
+//	        return h.Tr(attrs, h.Td(g.Text(u.Name)))
+//	    },
+//	))
+//
+// The handler renders only the rows for HTMX requests (see hxevents.IsHtmxRequest).
+// Cursor pagination works the same way with CursorFromRequest and Cursor.NextURL.
+//
+// # Dependencies
+//
+// Requires: stdlib (net/http, strconv), gomponents, hx, view, ctxkeys packages
+package pagination
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// Config configures query parameters and limits.
+type Config struct {
+	PageParam   string // Page number parameter (default "page")
+	SizeParam   string // Page size parameter (default "size")
+	CursorParam string // Cursor parameter (default "cursor")
+	DefaultSize int    // Page size if not requested (default 20)
+	MaxSize     int    // Upper bound for requested sizes (default 100)
+	Window      int    // Page links shown on each side of the current page (default 2, NoWindow for none)
+}
+
+// NoWindow as Config.Window shows only the first, current and last page
+// (0 selects the default window).
+const NoWindow = -1
+
+// DefaultConfig is used by New and CursorFromRequest.
+var DefaultConfig = Config{
+	PageParam:   "page",
+	SizeParam:   "size",
+	CursorParam: "cursor",
+	DefaultSize: 20,
+	MaxSize:     100,
+	Window:      2,
+}
+
+// withDefaults fills zero fields with the defaults and resolves NoWindow.
+func (c Config) withDefaults() Config {
+	if c.PageParam == "" {
+		c.PageParam = "page"
+	}
+	if c.SizeParam == "" {
+		c.SizeParam = "size"
+	}
+	if c.CursorParam == "" {
+		c.CursorParam = "cursor"
+	}
+	if c.DefaultSize <= 0 {
+		c.DefaultSize = 20
+	}
+	if c.MaxSize <= 0 {
+		c.MaxSize = 100
+	}
+	switch {
+	case c.Window == 0:
+		c.Window = 2
+	case c.Window < 0:
+		c.Window = 0
+	}
+	return c
+}
+
+// size reads the page size from the request, clamped to [1, MaxSize].
+func (c Config) size(req *http.Request) int {
+	size := queryInt(req, c.SizeParam, c.DefaultSize)
+	if size < 1 {
+		size = c.DefaultSize
+	}
+	if size > c.MaxSize {
+		size = c.MaxSize
+	}
+	return size
+}
+
+// Page is an offset-paginated page of a list with a known total.
+type Page struct {
+	Number int // Current page (1-based, clamped to [1, TotalPages])
+	Size   int // Items per page
+	Total  int // Total number of items
+
+	req  *http.Request
+	path string // App-relative path for links
+	cfg  Config
+}
+
+// New reads page and size from the request using DefaultConfig.
+func New(req *http.Request, total int) Page {
+	return DefaultConfig.New(req, total)
+}
+
+// New reads page and size from the request.
+// Invalid or out-of-range page numbers are clamped to the nearest page.
+func (c Config) New(req *http.Request, total int) Page {
+	c = c.withDefaults()
+	if total < 0 {
+		total = 0
+	}
+
+	p := Page{
+		Number: queryInt(req, c.PageParam, 1),
+		Size:   c.size(req),
+		Total:  total,
+		req:    req,
+		path:   requestPath(req),
+		cfg:    c,
+	}
+	if p.Number > p.TotalPages() {
+		p.Number = p.TotalPages()
+	}
+	if p.Number < 1 {
+		p.Number = 1
+	}
+	return p
+}
+
+// WithPath returns a copy of the page whose links point to the app-relative
+// path instead of the current request path (e.g. the full page when the
+// list is rendered by an htmx endpoint).
+func (p Page) WithPath(path string) Page {
+	p.path = path
+	return p
+}
+
+// Offset returns the number of items to skip.
+func (p Page) Offset() int { return (p.Number - 1) * p.Size }
+
+// Limit returns the number of items to fetch.
+func (p Page) Limit() int { return p.Size }
+
+// TotalPages returns the number of pages (at least 1).
+func (p Page) TotalPages() int {
+	if p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
+
+// HasPrev reports whether there is a previous page.
+func (p Page) HasPrev() bool { return p.Number > 1 }
+
+// HasNext reports whether there is a next page.
+func (p Page) HasNext() bool { return p.Number < p.TotalPages() }
+
+// URL returns the basePath-aware URL of page n, keeping the current query.
+func (p Page) URL(n int) string {
+	return view.URL(p.req, p.path).KeepQuery().
+		Set(p.cfg.PageParam, n).
+		Set(p.cfg.SizeParam, p.Size).
+		String()
+}
+
+// PrevURL returns the URL of the previous page, or "" on the first page.
+func (p Page) PrevURL() string {
+	if !p.HasPrev() {
+		return ""
+	}
+	return p.URL(p.Number - 1)
+}
+
+// NextURL returns the URL of the next page, or "" on the last page.
+func (p Page) NextURL() string {
+	if !p.HasNext() {
+		return ""
+	}
+	return p.URL(p.Number + 1)
+}
+
+// Pages returns the page numbers to show: the first and last page and a
+// window around the current page. Gaps are represented by 0.
+//
+// Example (page 5 of 10, window 2): [1 0 3 4 5 6 7 0 10]
+func (p Page) Pages() []int {
+	total := p.TotalPages()
+	from := max(1, p.Number-p.cfg.Window)
+	to := min(total, p.Number+p.cfg.Window)
+
+	var pages []int
+	if from > 1 {
+		pages = append(pages, 1)
+		if from > 2 {
+			pages = append(pages, 0)
+		}
+	}
+	for n := from; n <= to; n++ {
+		pages = append(pages, n)
+	}
+	if to < total {
+		if to < total-1 {
+			pages = append(pages, 0)
+		}
+		pages = append(pages, total)
+	}
+	return pages
+}
+
+// Cursor is a cursor-paginated page (keyset pagination) for lists without
+// a known total or with frequently inserted items.
+type Cursor struct {
+	After string // Cursor from the request ("" for the first page)
+	Size  int    // Items per page
+
+	req  *http.Request
+	path string
+	cfg  Config
+}
+
+// CursorFromRequest reads cursor and size from the request using DefaultConfig.
+func CursorFromRequest(req *http.Request) Cursor {
+	return DefaultConfig.CursorFromRequest(req)
+}
+
+// CursorFromRequest reads cursor and size from the request.
+func (c Config) CursorFromRequest(req *http.Request) Cursor {
+	c = c.withDefaults()
+	return Cursor{
+		After: req.URL.Query().Get(c.CursorParam),
+		Size:  c.size(req),
+		req:   req,
+		path:  requestPath(req),
+		cfg:   c,
+	}
+}
+
+// WithPath returns a copy of the cursor whose links point to the app-relative path.
+func (c Cursor) WithPath(path string) Cursor {
+	c.path = path
+	return c
+}
+
+// Limit returns the number of items per page. Fetch one more to detect
+// whether a next page exists (see NextURL).
+func (c Cursor) Limit() int { return c.Size }
+
+// NextURL returns the URL of the page starting after cursor next,
+// or "" if next is empty (no more items).
+//
+// Example:
+//
+//	items, _ := repo.ListAfter(ctx, c.After, c.Limit()+1)
+//	next := ""
+//	if len(items) > c.Limit() {
+//	    items = items[:c.Limit()]
+//	    next = items[len(items)-1].ID
+//	}
+//	url := c.NextURL(next)
+func (c Cursor) NextURL(next string) string {
+	if next == "" {
+		return ""
+	}
+	return view.URL(c.req, c.path).KeepQuery().
+		Set(c.cfg.CursorParam, next).
+		Set(c.cfg.SizeParam, c.Size).
+		String()
+}
+
+// requestPath returns the app-relative, escaped path of the current request
+// (view.URL expects escaped paths, so "/files/a%2Fb" stays one segment).
+func requestPath(req *http.Request) string {
+	p := req.URL.EscapedPath()
+	bp, _ := req.Context().Value(ctxkeys.BasePath).(string)
+	bp = strings.TrimSuffix(bp, "/")
+	if bp != "" && (p == bp || strings.HasPrefix(p, bp+"/")) {
+		p = strings.TrimPrefix(p, bp)
+	}
+	if p == "" {
+		p = "/"
+	}
+	return p
+}
+
+// queryInt reads an integer query parameter, returning def if missing or invalid.
+func queryInt(req *http.Request, key string, def int) int {
+	v, err := strconv.Atoi(req.URL.Query().Get(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
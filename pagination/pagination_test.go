@@ -0,0 +1,175 @@
+package pagination
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// newRequest creates a request with basePath "/app".
+func newRequest(target string) *http.Request {
+	req := httptest.NewRequest("GET", target, nil)
+	return req.WithContext(context.WithValue(req.Context(), ctxkeys.BasePath, "/app"))
+}
+
+// render renders a node to a string.
+func render(t *testing.T, n g.Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := n.Render(&buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	return buf.String()
+}
+
+// TestNew tests reading and clamping page and size
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		total          int
+		expectedNumber int
+		expectedSize   int
+		expectedOffset int
+	}{
+		{"defaults", "/users", 95, 1, 20, 0},
+		{"page and size", "/users?page=3&size=10", 95, 3, 10, 20},
+		{"page beyond last", "/users?page=99&size=10", 95, 10, 10, 90},
+		{"negative page", "/users?page=-1", 95, 1, 20, 0},
+		{"invalid page", "/users?page=abc", 95, 1, 20, 0},
+		{"size above max", "/users?size=1000", 95, 1, 100, 0},
+		{"zero size", "/users?size=0", 95, 1, 20, 0},
+		{"empty list", "/users?page=2", 0, 1, 20, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(newRequest(tt.target), tt.total)
+			if p.Number != tt.expectedNumber || p.Size != tt.expectedSize || p.Offset() != tt.expectedOffset {
+				t.Errorf("got page %d size %d offset %d, expected %d/%d/%d",
+					p.Number, p.Size, p.Offset(), tt.expectedNumber, tt.expectedSize, tt.expectedOffset)
+			}
+		})
+	}
+}
+
+// TestPage_URLs tests basePath-aware links that keep the query
+func TestPage_URLs(t *testing.T) {
+	p := New(newRequest("/app/users?q=alice&page=2&size=10"), 35)
+
+	if got, want := p.PrevURL(), "/app/users?page=1&q=alice&size=10"; got != want {
+		t.Errorf("PrevURL = %q, expected %q", got, want)
+	}
+	if got, want := p.NextURL(), "/app/users?page=3&q=alice&size=10"; got != want {
+		t.Errorf("NextURL = %q, expected %q", got, want)
+	}
+
+	last := New(newRequest("/users?page=4&size=10"), 35)
+	if last.HasNext() || last.NextURL() != "" {
+		t.Error("last page should have no next URL")
+	}
+
+	escaped := New(newRequest("/app/files/a%2Fb%20c?page=2&size=10"), 35)
+	if got, want := escaped.NextURL(), "/app/files/a%2Fb%20c?page=3&size=10"; got != want {
+		t.Errorf("NextURL with escaped path = %q, expected %q", got, want)
+	}
+
+	moved := p.WithPath("/people")
+	if got, want := moved.URL(1), "/app/people?page=1&q=alice&size=10"; got != want {
+		t.Errorf("URL with path = %q, expected %q", got, want)
+	}
+}
+
+// TestPage_Pages tests the page window with gaps
+func TestPage_Pages(t *testing.T) {
+	tests := []struct {
+		page     string
+		total    int
+		expected []int
+	}{
+		{"1", 30, []int{1, 2, 3}},
+		{"5", 100, []int{1, 0, 3, 4, 5, 6, 7, 0, 10}},
+		{"3", 100, []int{1, 2, 3, 4, 5, 0, 10}},
+		{"4", 100, []int{1, 2, 3, 4, 5, 6, 0, 10}},
+		{"10", 100, []int{1, 0, 8, 9, 10}},
+	}
+
+	for _, tt := range tests {
+		p := New(newRequest("/x?size=10&page="+tt.page), tt.total)
+		if got := p.Pages(); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("page %s of %d: got %v, expected %v", tt.page, tt.total, got, tt.expected)
+		}
+	}
+}
+
+// TestPage_PagesWindow tests custom, default and empty windows
+func TestPage_PagesWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   int
+		expected []int
+	}{
+		{"default", 0, []int{1, 0, 3, 4, 5, 6, 7, 0, 10}},
+		{"one", 1, []int{1, 0, 4, 5, 6, 0, 10}},
+		{"none", NoWindow, []int{1, 0, 5, 0, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Config{Window: tt.window}.New(newRequest("/x?size=10&page=5"), 100)
+			if got := p.Pages(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestNav tests navigation rendering
+func TestNav(t *testing.T) {
+	got := render(t, Nav(New(newRequest("/users?page=1&size=10"), 25)))
+
+	for _, want := range []string{
+		`<nav class="pagination" aria-label="Pagination">`,
+		`<span class="prev" aria-disabled="true">`,
+		`<a href="/app/users?page=1&amp;size=10" aria-current="page">1</a>`,
+		`<a class="next" href="/app/users?page=2&amp;size=10" rel="next">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in %s", want, got)
+		}
+	}
+
+	if n := Nav(New(newRequest("/users"), 5)); n != nil {
+		t.Error("expected no navigation for a single page")
+	}
+}
+
+// TestInfiniteScroll tests that only the last row gets the scroll attributes
+func TestInfiniteScroll(t *testing.T) {
+	c := CursorFromRequest(newRequest("/users?cursor=b&size=2"))
+	if c.After != "b" || c.Limit() != 2 {
+		t.Fatalf("unexpected cursor %+v", c)
+	}
+
+	row := func(s string, attrs g.Node) g.Node { return h.Tr(attrs, h.Td(g.Text(s))) }
+
+	got := render(t, InfiniteScroll([]string{"c", "d"}, c.NextURL("d"), row))
+	expected := `<tr><td>c</td></tr>` +
+		`<tr hx-get="/app/users?cursor=d&amp;size=2" hx-trigger="revealed" hx-swap="afterend"><td>d</td></tr>`
+	if got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+
+	if got := render(t, InfiniteScroll([]string{"e"}, c.NextURL(""), row)); got != `<tr><td>e</td></tr>` {
+		t.Errorf("last page should have no scroll attributes, got %s", got)
+	}
+}
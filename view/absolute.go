@@ -0,0 +1,80 @@
+package view
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// ErrNoOrigin is returned by AbsoluteURLErr if middleware.Origin is not used.
+var ErrNoOrigin = errors.New("view: AbsoluteURL requires middleware.Origin")
+
+// AbsoluteURL returns an absolute, basePath-aware URL (scheme://host/base/path).
+//
+// Use it where relative URLs do not work: emails, canonical links,
+// OpenGraph tags, OAuth callbacks.
+//
+// The origin comes from middleware.Origin (configured public origin, or an
+// allowed host from the request or forwarded headers of trusted proxies).
+// The client-controlled Host header is never used directly.
+//
+// Panics with ErrNoOrigin if middleware.Origin is not used; the middleware
+// is set up once per router, so the first request shows it. Use
+// AbsoluteURLErr to return the error from a handler instead.
+//
+// Example:
+//
+//	view.AbsoluteURL(req, "/confirm?token=abc")  // "https://example.com/app/confirm?token=abc"
+func AbsoluteURL(req *http.Request, p string) string {
+	u, err := AbsoluteURLErr(req, p)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// AbsoluteURLErr is AbsoluteURL with an error instead of a panic:
+//
+//	canonical, err := view.AbsoluteURLErr(ctx.Req, "/posts/42")
+//	if err != nil {
+//	    return err
+//	}
+func AbsoluteURLErr(req *http.Request, p string) (string, error) {
+	if isAbsoluteURL(p) {
+		return p, nil
+	}
+	o, err := origin(req)
+	if err != nil {
+		return "", err
+	}
+	return o + joinBasePath(req, p), nil
+}
+
+// Absolute returns the URL with scheme and host (see AbsoluteURL).
+// Panics like AbsoluteURL; see AbsoluteErr.
+func (b *URLBuilder) Absolute() string {
+	u, err := b.AbsoluteErr()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// AbsoluteErr is Absolute with an error instead of a panic.
+func (b *URLBuilder) AbsoluteErr() (string, error) {
+	o, err := origin(b.req)
+	if err != nil {
+		return "", err
+	}
+	return o + b.format(joinBasePath), nil
+}
+
+// origin returns the request origin stored by middleware.Origin.
+func origin(req *http.Request) (string, error) {
+	o, _ := req.Context().Value(ctxkeys.Origin).(string)
+	if o == "" {
+		return "", ErrNoOrigin
+	}
+	return o, nil
+}
@@ -0,0 +1,203 @@
+package view_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/middleware"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+// TestAbsoluteURL tests absolute URL generation for proxy and direct access
+func TestAbsoluteURL(t *testing.T) {
+	proxies := middleware.MustTrustedProxies("10.0.0.0/8")
+
+	allowed := []string{"localhost", "example.com", "internal:8080"}
+
+	tests := []struct {
+		name       string
+		cfg        middleware.OriginConfig
+		target     string
+		remoteAddr string
+		headers    map[string]string
+		base       string
+		path       string
+		expected   string
+		status     int
+	}{
+		{
+			name:       "direct http",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed},
+			target:     "http://localhost:8080/",
+			remoteAddr: "127.0.0.1:5000",
+			path:       "/login",
+			expected:   "http://localhost:8080/login",
+		},
+		{
+			name:       "direct https with base path",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed},
+			target:     "https://example.com/",
+			remoteAddr: "192.0.2.1:5000",
+			base:       "/app",
+			path:       "/confirm?token=abc",
+			expected:   "https://example.com/app/confirm?token=abc",
+		},
+		{
+			name:       "direct access ignores forwarded headers",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed, TrustedProxies: proxies},
+			target:     "http://internal:8080/",
+			remoteAddr: "192.0.2.1:5000",
+			headers:    map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.com"},
+			path:       "/login",
+			expected:   "http://internal:8080/login",
+		},
+		{
+			name:       "trusted proxy",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed, TrustedProxies: proxies},
+			target:     "http://internal:8080/",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "example.com"},
+			base:       "/app",
+			path:       "/login",
+			expected:   "https://example.com/app/login",
+		},
+		{
+			name:       "trusted proxy with invalid host",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed, TrustedProxies: proxies},
+			target:     "http://internal:8080/",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-Host": "evil.com/path"},
+			path:       "/login",
+			expected:   "http://internal:8080/login",
+		},
+		{
+			name:       "forged host rejected",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed},
+			target:     "http://evil.com/",
+			remoteAddr: "192.0.2.1:5000",
+			path:       "/login",
+			status:     http.StatusBadRequest,
+		},
+		{
+			name:       "forged forwarded host rejected",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed, TrustedProxies: proxies},
+			target:     "http://internal:8080/",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-Host": "evil.com"},
+			path:       "/login",
+			status:     http.StatusBadRequest,
+		},
+		{
+			name:       "host with other port rejected",
+			cfg:        middleware.OriginConfig{AllowedHosts: allowed},
+			target:     "http://internal:9090/",
+			remoteAddr: "192.0.2.1:5000",
+			path:       "/login",
+			status:     http.StatusBadRequest,
+		},
+		{
+			name:       "public origin wins",
+			cfg:        middleware.OriginConfig{PublicOrigin: "https://www.example.com/ignored", TrustedProxies: proxies},
+			target:     "http://internal:8080/",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-Host": "other.example.com"},
+			base:       "/app",
+			path:       "login",
+			expected:   "https://www.example.com/app/login",
+		},
+		{
+			name:     "already absolute",
+			cfg:      middleware.OriginConfig{AllowedHosts: allowed},
+			target:   "http://localhost/",
+			path:     "https://cdn.example.com/x.js",
+			expected: "https://cdn.example.com/x.js",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if tt.base != "" {
+				req = req.WithContext(context.WithValue(req.Context(), ctxkeys.BasePath, tt.base))
+			}
+
+			var got string
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = view.AbsoluteURL(r, tt.path)
+			})
+			rec := httptest.NewRecorder()
+			middleware.Origin(tt.cfg)(h).ServeHTTP(rec, req)
+
+			if tt.status != 0 {
+				if rec.Code != tt.status {
+					t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+				}
+				if got != "" {
+					t.Errorf("handler should not run, got %q", got)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestAbsoluteURL_RequiresOrigin tests that the Host header is never used
+// without middleware.Origin
+func TestAbsoluteURL_RequiresOrigin(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://evil.com/", nil)
+
+	t.Run("no middleware", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic without middleware.Origin")
+			}
+		}()
+		view.AbsoluteURL(req, "/login")
+	})
+
+	t.Run("error variants", func(t *testing.T) {
+		if u, err := view.AbsoluteURLErr(req, "/login"); !errors.Is(err, view.ErrNoOrigin) || u != "" {
+			t.Errorf("AbsoluteURLErr: expected ErrNoOrigin, got %q %v", u, err)
+		}
+		if u, err := view.URL(req, "/login").AbsoluteErr(); !errors.Is(err, view.ErrNoOrigin) || u != "" {
+			t.Errorf("AbsoluteErr: expected ErrNoOrigin, got %q %v", u, err)
+		}
+		if u, err := view.AbsoluteURLErr(req, "https://example.com/x"); err != nil || u != "https://example.com/x" {
+			t.Errorf("absolute input should not need an origin, got %q %v", u, err)
+		}
+	})
+
+	t.Run("no public origin or allowed hosts", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic without PublicOrigin or AllowedHosts")
+			}
+		}()
+		middleware.Origin(middleware.OriginConfig{})
+	})
+}
+
+// TestURLBuilder_Absolute tests absolute URLs from the builder
+func TestURLBuilder_Absolute(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://example.com/list?page=2", nil)
+	ctx := context.WithValue(req.Context(), ctxkeys.BasePath, "/app")
+	req = req.WithContext(context.WithValue(ctx, ctxkeys.Origin, "https://example.com"))
+
+	got := view.URL(req, "/users", 7).KeepQuery().Absolute()
+	if got != "https://example.com/app/users/7?page=2" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package view
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// ActiveClass is the CSS class added by NavLink and ActiveAttrs.
+var ActiveClass = "active"
+
+// IsActive reports whether the current page is exactly the app-relative
+// path p (basePath-aware, trailing slashes ignored).
+//
+// For HTMX requests the current page is taken from HX-Current-URL, because
+// the request URL points to the htmx endpoint, not the page being viewed.
+//
+// Example:
+//
+//	view.IsActive(req, "/users")  // true on /app/users, false on /app/users/42
+func IsActive(req *http.Request, p string) bool {
+	return trimSlash(currentPath(req)) == trimSlash(targetPath(req, p))
+}
+
+// IsActivePrefix reports whether the current page is prefix or below it
+// (segment-aware: "/users" matches "/users/42" but not "/users-admin").
+//
+// Example:
+//
+//	view.IsActivePrefix(req, "/users")  // true on /app/users and /app/users/42
+func IsActivePrefix(req *http.Request, prefix string) bool {
+	current := trimSlash(currentPath(req))
+	target := trimSlash(targetPath(req, prefix))
+
+	if target == "/" {
+		return true
+	}
+	return current == target || strings.HasPrefix(current, target+"/")
+}
+
+// ActiveAttrs returns aria-current="page" and the active class if active is
+// true, otherwise nil. Use with IsActive/IsActivePrefix on custom elements:
+//
+//	h.Li(view.ActiveAttrs(view.IsActivePrefix(req, "/users")), ...)
+func ActiveAttrs(active bool) g.Node {
+	if !active {
+		return nil
+	}
+	return g.Group([]g.Node{
+		h.Aria("current", "page"),
+		h.Class(ActiveClass),
+	})
+}
+
+// NavLink renders a basePath-aware link that is marked active (aria-current,
+// ActiveClass) when the current page is p or below it.
+// Use p = "/" for the home link; it is only active on the home page.
+//
+// Example:
+//
+//	view.NavLink(req, "/users", g.Text("Users"))
+//	// <a href="/app/users" aria-current="page" class="active">Users</a>
+func NavLink(req *http.Request, p string, children ...g.Node) g.Node {
+	active := IsActivePrefix(req, p)
+	if trimSlash(p) == "/" {
+		active = IsActive(req, p)
+	}
+
+	return h.A(
+		h.Href(URLString(req, p)),
+		ActiveAttrs(active),
+		g.Group(children),
+	)
+}
+
+// currentPath returns the escaped path of the page being viewed, including
+// the basePath.
+func currentPath(req *http.Request) string {
+	if req.Header.Get("HX-Request") == "true" {
+		if u, err := url.Parse(req.Header.Get("HX-Current-URL")); err == nil && u.Path != "" {
+			return u.EscapedPath()
+		}
+	}
+
+	// Full-page load: the path may have been stripped by the proxy or
+	// middleware.StripBasePath - restore the basePath for comparison
+	p := req.URL.EscapedPath()
+	bp := basePath(req)
+	if bp != "" && p != bp && !strings.HasPrefix(p, bp+"/") {
+		p = bp + p
+	}
+	return p
+}
+
+// targetPath returns the basePath-aware path of p without query and fragment.
+func targetPath(req *http.Request, p string) string {
+	u := withBasePath(req, p)
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	return u
+}
+
+// trimSlash removes a trailing slash (except for the root path).
+func trimSlash(p string) string {
+	if len(p) > 1 {
+		return strings.TrimSuffix(p, "/")
+	}
+	if p == "" {
+		return "/"
+	}
+	return p
+}
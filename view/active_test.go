@@ -0,0 +1,71 @@
+package view
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestIsActive tests exact and prefix matching
+func TestIsActive(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		base           string
+		currentURL     string // HX-Current-URL (makes it an HTMX request)
+		path           string
+		expectedExact  bool
+		expectedPrefix bool
+	}{
+		{"exact", "/users", "", "", "/users", true, true},
+		{"trailing slash", "/users/", "", "", "/users", true, true},
+		{"child", "/users/42", "", "", "/users", false, true},
+		{"similar prefix", "/users-admin", "", "", "/users", false, false},
+		{"base path (stripped request)", "/users", "/app", "", "/users", true, true},
+		{"base path (full request path)", "/app/users/7", "/app", "", "/users", false, true},
+		{"home", "/", "/app", "", "/", true, true},
+		{"home on subpage", "/users", "/app", "", "/", false, true},
+		{"query ignored", "/users?page=2", "", "", "/users?sort=name", true, true},
+		{"htmx uses current URL", "/htmx/users/list", "/app", "https://example.com/app/users", "/users", true, true},
+		{"htmx other page", "/htmx/users/list", "/app", "https://example.com/app/settings", "/users", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(tt.target, tt.base)
+			if tt.currentURL != "" {
+				req.Header.Set("HX-Request", "true")
+				req.Header.Set("HX-Current-URL", tt.currentURL)
+			}
+
+			if got := IsActive(req, tt.path); got != tt.expectedExact {
+				t.Errorf("IsActive(%q) = %v, expected %v", tt.path, got, tt.expectedExact)
+			}
+			if got := IsActivePrefix(req, tt.path); got != tt.expectedPrefix {
+				t.Errorf("IsActivePrefix(%q) = %v, expected %v", tt.path, got, tt.expectedPrefix)
+			}
+		})
+	}
+}
+
+// TestNavLink tests the active link rendering
+func TestNavLink(t *testing.T) {
+	req := newRequest("/users/42", "/app")
+
+	render := func(p string) string {
+		var sb strings.Builder
+		if err := NavLink(req, p).Render(&sb); err != nil {
+			t.Fatalf("Render() failed: %v", err)
+		}
+		return sb.String()
+	}
+
+	if got := render("/users"); got != `<a href="/app/users" aria-current="page" class="active"></a>` {
+		t.Errorf("unexpected active link %q", got)
+	}
+	if got := render("/settings"); got != `<a href="/app/settings"></a>` {
+		t.Errorf("unexpected inactive link %q", got)
+	}
+	if got := render("/"); got != `<a href="/app/"></a>` {
+		t.Errorf("home link should only be active on the home page, got %q", got)
+	}
+}
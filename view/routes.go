@@ -0,0 +1,332 @@
+package view
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	g "maragu.dev/gomponents"
+)
+
+// Registry is a registry of named routes for reverse routing.
+//
+// Register routes with a name and a Chi-style pattern at startup, then
+// generate URLs by name instead of hardcoding paths:
+//
+//	// main.go
+//	r.Get(view.Routes.Add("user.show", "/users/{id}"), wrapper.Wrap(users.Show))
+//
+//	// views
+//	A(view.RouteHref(req, "user.show", "id", 42))  // href="/app/users/42"
+//
+// Renaming an endpoint then only touches the registration.
+type Registry struct {
+	mu     sync.RWMutex
+	routes map[string]*route
+}
+
+// route is a parsed route pattern.
+type route struct {
+	pattern string
+	parts   []routePart
+	params  []string // Parameter names in order
+}
+
+// routePart is either a literal path piece or a parameter.
+type routePart struct {
+	literal  string
+	param    string // Parameter name (empty for literals)
+	wildcard bool   // Chi catch-all "*"
+}
+
+// Routes is the default registry used by Route.
+var Routes = NewRegistry()
+
+// NewRegistry creates an empty route registry.
+func NewRegistry() *Registry {
+	return &Registry{routes: make(map[string]*route)}
+}
+
+// Register adds a named route.
+//
+// Patterns use Chi syntax: "{id}", "{id:[0-9]+}" (regexp is ignored for URL
+// generation) and a trailing "*" catch-all (parameter name "*").
+//
+// Returns an error for empty names, duplicate names and malformed patterns
+// (unbalanced braces, parameter names that are not identifiers, duplicate
+// parameters, "*" not at the end).
+func (r *Registry) Register(name, pattern string) error {
+	if name == "" {
+		return fmt.Errorf("view: route name must not be empty (pattern %q)", pattern)
+	}
+
+	rt, err := parsePattern(pattern)
+	if err != nil {
+		return fmt.Errorf("view: route %q: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.routes[name]; ok {
+		return fmt.Errorf("view: duplicate route name %q (%q and %q)", name, existing.pattern, pattern)
+	}
+	r.routes[name] = rt
+	return nil
+}
+
+// Add registers a named route and returns the pattern, so registration and
+// routing happen in one place. Panics on error - routes are registered at
+// startup and a broken route table should fail fast.
+//
+// Example:
+//
+//	r.Get(view.Routes.Add("user.show", "/users/{id}"), handler)
+func (r *Registry) Add(name, pattern string) string {
+	if err := r.Register(name, pattern); err != nil {
+		panic(err)
+	}
+	return pattern
+}
+
+// Path returns the app-relative path (without basePath) for a named route.
+//
+// params are name/value pairs; values are formatted with fmt.Sprint and
+// path-escaped (the "*" catch-all is escaped per segment). Returns an error
+// if the route is unknown, a parameter (including "*") is missing or
+// unknown, or params has an odd length. Pass "*", "" for an empty catch-all.
+func (r *Registry) Path(name string, params ...any) (string, error) {
+	r.mu.RLock()
+	rt, ok := r.routes[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("view: unknown route %q", name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("view: route %q: params must be name/value pairs, got %d values", name, len(params))
+	}
+
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		key, ok := params[i].(string)
+		if !ok {
+			return "", fmt.Errorf("view: route %q: param name at position %d must be a string, got %T", name, i, params[i])
+		}
+		values[key] = fmt.Sprint(params[i+1])
+	}
+
+	var sb strings.Builder
+	for _, part := range rt.parts {
+		if part.param == "" {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		v, ok := values[part.param]
+		if !ok {
+			return "", fmt.Errorf("view: route %q (%s): missing param %q", name, rt.pattern, part.param)
+		}
+		delete(values, part.param)
+
+		if part.wildcard {
+			segments := strings.Split(v, "/")
+			for i, s := range segments {
+				segments[i] = url.PathEscape(s)
+			}
+			sb.WriteString(strings.Join(segments, "/"))
+		} else {
+			sb.WriteString(url.PathEscape(v))
+		}
+	}
+
+	for key := range values {
+		return "", fmt.Errorf("view: route %q (%s): unknown param %q", name, rt.pattern, key)
+	}
+
+	return sb.String(), nil
+}
+
+// URL returns the basePath-aware URL for a named route.
+// See Path for params and errors.
+func (r *Registry) URL(req *http.Request, name string, params ...any) (string, error) {
+	p, err := r.Path(name, params...)
+	if err != nil {
+		return "", err
+	}
+	return withBasePath(req, p), nil
+}
+
+// Params returns the parameter names of a named route in order.
+// Returns nil if the route is unknown.
+func (r *Registry) Params(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rt, ok := r.routes[name]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), rt.params...)
+}
+
+// Href returns an href attribute with the basePath-aware URL for a named
+// route. If the route is unknown or params do not match the pattern, the
+// attribute's Render returns the error, so it reaches the handler through
+// ctx.Render instead of panicking mid-render.
+func (r *Registry) Href(req *http.Request, name string, params ...any) g.Node {
+	u, err := r.URL(req, name, params...)
+	return routeAttr{name: "href", value: u, err: err}
+}
+
+// RouteHref returns an href attribute for a route of the default registry
+// (see Registry.Href). Use it in views instead of Route.
+//
+// Example:
+//
+//	A(view.RouteHref(req, "user.show", "id", 42), g.Text("Profile"))  // href="/app/users/42"
+func RouteHref(req *http.Request, name string, params ...any) g.Node {
+	return Routes.Href(req, name, params...)
+}
+
+// Route returns the basePath-aware URL for a route of the default registry,
+// for redirects and other code outside of rendering.
+//
+// params are name/value pairs. Panics if the route is unknown or params do
+// not match the pattern, so a redirect to a renamed route fails in the
+// handler that issues it rather than sending users to a 404. In views use
+// RouteHref, which reports the error through Render; use Routes.URL to
+// handle it yourself.
+//
+// Example:
+//
+//	http.Redirect(w, r, view.Route(req, "user.show", "id", 42), http.StatusSeeOther)
+func Route(req *http.Request, name string, params ...any) string {
+	u, err := Routes.URL(req, name, params...)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// routeAttr is an attribute node that fails on Render if the URL could
+// not be built.
+type routeAttr struct {
+	name, value string
+	err         error
+}
+
+// Render writes the attribute or returns the URL error.
+func (a routeAttr) Render(w io.Writer) error {
+	if a.err != nil {
+		return a.err
+	}
+	return g.Attr(a.name, a.value).Render(w)
+}
+
+// Type marks the node as an attribute for gomponents elements.
+func (a routeAttr) Type() g.NodeType {
+	return g.AttributeType
+}
+
+// parsePattern splits a Chi-style pattern into literals and parameters.
+func parsePattern(pattern string) (*route, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern %q must start with /", pattern)
+	}
+
+	rt := &route{pattern: pattern}
+	seen := make(map[string]bool)
+	rest := pattern
+
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		star := strings.IndexByte(rest, '*')
+
+		// Literals end at the next { or *; a } there is unbalanced
+		literalEnd := len(rest)
+		for _, i := range []int{open, star} {
+			if i >= 0 && i < literalEnd {
+				literalEnd = i
+			}
+		}
+		if strings.ContainsRune(rest[:literalEnd], '}') {
+			return nil, fmt.Errorf("pattern %q: unexpected }", pattern)
+		}
+
+		// Catch-all: only allowed as the last character
+		if star >= 0 && (open < 0 || star < open) {
+			if star != len(rest)-1 {
+				return nil, fmt.Errorf("pattern %q: catch-all * must be at the end", pattern)
+			}
+			rt.parts = append(rt.parts, routePart{literal: rest[:star]})
+			rt.parts = append(rt.parts, routePart{param: "*", wildcard: true})
+			rt.params = append(rt.params, "*")
+			break
+		}
+
+		if open < 0 {
+			rt.parts = append(rt.parts, routePart{literal: rest})
+			break
+		}
+
+		end := closingBrace(rest, open)
+		if end < 0 {
+			return nil, fmt.Errorf("pattern %q: unclosed {", pattern)
+		}
+
+		name, _, _ := strings.Cut(rest[open+1:end], ":")
+		if name == "" {
+			return nil, fmt.Errorf("pattern %q: empty param name", pattern)
+		}
+		if !isParamName(name) {
+			return nil, fmt.Errorf("pattern %q: invalid param name %q", pattern, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pattern %q: duplicate param %q", pattern, name)
+		}
+		seen[name] = true
+
+		rt.parts = append(rt.parts, routePart{literal: rest[:open]})
+		rt.parts = append(rt.parts, routePart{param: name})
+		rt.params = append(rt.params, name)
+		rest = rest[end+1:]
+	}
+
+	return rt, nil
+}
+
+// isParamName reports whether name is an identifier (letters, digits and
+// underscores, not starting with a digit).
+func isParamName(name string) bool {
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// closingBrace returns the index of the brace closing the one at open,
+// honoring nested braces in regexps like {id:[0-9]{3}}.
+func closingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,141 @@
+package view
+
+import (
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// TestRegistry tests named route registration and URL generation
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("home", "/")
+	reg.Add("user.show", "/users/{id}")
+	reg.Add("user.post", "/users/{id:[0-9]+}/posts/{slug}")
+	reg.Add("files", "/files/*")
+
+	req := newRequest("/", "/app")
+
+	tests := []struct {
+		name     string
+		route    string
+		params   []any
+		expected string
+	}{
+		{"static", "home", nil, "/app/"},
+		{"one param", "user.show", []any{"id", 42}, "/app/users/42"},
+		{"escaped param", "user.show", []any{"id", "a/b c"}, "/app/users/a%2Fb%20c"},
+		{"regexp param", "user.post", []any{"slug", "hello", "id", 7}, "/app/users/7/posts/hello"},
+		{"catch-all", "files", []any{"*", "docs/my file.pdf"}, "/app/files/docs/my%20file.pdf"},
+		{"empty catch-all", "files", []any{"*", ""}, "/app/files/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reg.URL(req, tt.route, tt.params...)
+			if err != nil {
+				t.Fatalf("URL() failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	if params := reg.Params("user.post"); strings.Join(params, ",") != "id,slug" {
+		t.Errorf("unexpected params %v", params)
+	}
+}
+
+// TestRegistry_Errors tests fail-fast behavior
+func TestRegistry_Errors(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("user.show", "/users/{id}")
+	reg.Add("files", "/files/*")
+
+	if err := reg.Register("user.show", "/people/{id}"); err == nil {
+		t.Error("expected error for duplicate route name")
+	}
+
+	for _, pattern := range []string{
+		"users", "/users/{id", "/users/{}", "/a/{x}/{x}", "/files/*/x",
+		"/users/id}", "/users/{id}}", "/a}/*", "/users/{a b}", "/users/{1id}", "/users/{id/x}",
+	} {
+		if err := reg.Register("bad", pattern); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		route  string
+		params []any
+	}{
+		{"unknown route", "user.missing", nil},
+		{"missing param", "user.show", nil},
+		{"missing catch-all", "files", nil},
+		{"unknown param", "user.show", []any{"id", 1, "extra", 2}},
+		{"odd params", "user.show", []any{"id"}},
+		{"non-string name", "user.show", []any{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := reg.Path(tt.route, tt.params...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	t.Run("Add panics on duplicate", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		reg.Add("user.show", "/u/{id}")
+	})
+}
+
+// TestRoute tests the default registry helper
+func TestRoute(t *testing.T) {
+	defer func(r *Registry) { Routes = r }(Routes)
+	Routes = NewRegistry()
+	Routes.Add("user.show", "/users/{id}")
+
+	if got := Route(newRequest("/", "/app"), "user.show", "id", 42); got != "/app/users/42" {
+		t.Errorf("got %q, expected /app/users/42", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for missing param")
+		}
+	}()
+	Route(newRequest("/", ""), "user.show")
+}
+
+// TestRouteHref tests that route errors surface from Render instead of panicking
+func TestRouteHref(t *testing.T) {
+	defer func(r *Registry) { Routes = r }(Routes)
+	Routes = NewRegistry()
+	Routes.Add("user.show", "/users/{id}")
+
+	req := newRequest("/", "/app")
+
+	var sb strings.Builder
+	if err := h.A(RouteHref(req, "user.show", "id", 42), g.Text("Profile")).Render(&sb); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if sb.String() != `<a href="/app/users/42">Profile</a>` {
+		t.Errorf("unexpected output %q", sb.String())
+	}
+
+	sb.Reset()
+	err := h.A(RouteHref(req, "user.show"), g.Text("Profile")).Render(&sb)
+	if err == nil || !strings.Contains(err.Error(), `missing param "id"`) {
+		t.Errorf("expected missing param error, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package view
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// Query parameters added by SignedURL.
+const (
+	SignatureParam = "signature"
+	ExpiresParam   = "expires"
+)
+
+// Signature verification errors.
+var (
+	ErrSignatureMissing = errors.New("view: signature missing")
+	ErrSignatureInvalid = errors.New("view: signature invalid")
+	ErrSignatureExpired = errors.New("view: signature expired")
+)
+
+// ErrNoSigningKey is returned by SignedURLErr if middleware.URLSigning is
+// not used.
+var ErrNoSigningKey = errors.New("view: SignedURL requires middleware.URLSigning")
+
+// SignedURL returns a basePath-aware URL with an expiry and an HMAC-SHA256
+// signature, for links that must not be forged or reused forever (downloads,
+// email confirmation, unsubscribe).
+//
+// The signing key comes from middleware.URLSigning. The signature covers the
+// app-relative path and all query parameters, so changing the base path does
+// not invalidate links. The path may be given raw ("/files/a b.pdf") or
+// escaped ("/files/a%20b.pdf"); both are signed in the escaped form the
+// browser sends back.
+//
+// Panics with ErrNoSigningKey if middleware.URLSigning is not used, rather
+// than returning a link that fails verification for every user. Use
+// SignedURLErr to return the error from a handler instead.
+//
+// Example:
+//
+//	link := view.SignedURL(req, "/downloads/report.pdf?user=42", 24*time.Hour)
+//	// "/app/downloads/report.pdf?expires=1767225600&user=42&signature=..."
+func SignedURL(req *http.Request, p string, ttl time.Duration) string {
+	u, err := SignedURLErr(req, p, ttl)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// SignedURLErr is SignedURL with an error instead of a panic.
+func SignedURLErr(req *http.Request, p string, ttl time.Duration) (string, error) {
+	key := signingKey(req)
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	path, rawQuery, _ := strings.Cut(p, "?")
+	path = escapedPath(path)
+	query, _ := url.ParseQuery(rawQuery)
+	query.Del(SignatureParam)
+	query.Set(ExpiresParam, strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+
+	canonical := query.Encode()
+	sig := sign(key, path, canonical)
+
+	return joinBasePath(req, path) + "?" + canonical + "&" + SignatureParam + "=" + sig, nil
+}
+
+// AbsoluteSignedURL is like SignedURL but returns an absolute URL (for emails).
+// Panics like SignedURL and AbsoluteURL if their middleware is missing.
+func AbsoluteSignedURL(req *http.Request, p string, ttl time.Duration) string {
+	u, err := AbsoluteSignedURLErr(req, p, ttl)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// AbsoluteSignedURLErr is AbsoluteSignedURL with an error (ErrNoOrigin or
+// ErrNoSigningKey) instead of a panic.
+func AbsoluteSignedURLErr(req *http.Request, p string, ttl time.Duration) (string, error) {
+	o, err := origin(req)
+	if err != nil {
+		return "", err
+	}
+	u, err := SignedURLErr(req, p, ttl)
+	if err != nil {
+		return "", err
+	}
+	return o + u, nil
+}
+
+// VerifySignedURL checks the signature and expiry of the current request.
+//
+// Returns ErrSignatureMissing, ErrSignatureInvalid or ErrSignatureExpired.
+// Usually called through middleware.RequireSignedURL.
+func VerifySignedURL(req *http.Request) error {
+	key := signingKey(req)
+	if key == nil {
+		return ErrSignatureInvalid
+	}
+
+	query := req.URL.Query()
+	sig := query.Get(SignatureParam)
+	if sig == "" {
+		return ErrSignatureMissing
+	}
+	query.Del(SignatureParam)
+	canonical := query.Encode()
+
+	valid := false
+	for _, p := range candidatePaths(req) {
+		if hmac.Equal([]byte(sig), []byte(sign(key, p, canonical))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrSignatureInvalid
+	}
+
+	// Expiry is checked after the signature, so it cannot be tampered with
+	expires, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if time.Now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// candidatePaths returns the app-relative paths the request may have been
+// signed for: the path as received (prefix stripped by the proxy or
+// middleware.StripBasePath) and, if it still contains the basePath, without it.
+func candidatePaths(req *http.Request) []string {
+	p := req.URL.EscapedPath()
+	paths := []string{p}
+
+	if bp := basePath(req); bp != "" {
+		if rest, ok := strings.CutPrefix(p, bp); ok && strings.HasPrefix(rest, "/") {
+			paths = append(paths, rest)
+		}
+	}
+	return paths
+}
+
+// escapedPath returns the escaped form of a raw or escaped path, matching
+// url.URL.EscapedPath of the request that follows the link.
+func escapedPath(p string) string {
+	u, err := url.Parse(p)
+	if err != nil {
+		return (&url.URL{Path: p}).EscapedPath()
+	}
+	return u.EscapedPath()
+}
+
+// sign computes the base64url HMAC-SHA256 of path and canonical query.
+// Paths are normalized to a leading slash, matching withBasePath.
+func sign(key []byte, path, canonicalQuery string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("/" + strings.TrimPrefix(path, "/")))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(canonicalQuery))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signingKey returns the URL signing key from the request context.
+func signingKey(req *http.Request) []byte {
+	key, _ := req.Context().Value(ctxkeys.SigningKey).([]byte)
+	if len(key) == 0 {
+		return nil
+	}
+	return key
+}
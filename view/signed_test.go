@@ -0,0 +1,134 @@
+package view_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+	"github.com/axelrhd/hagg-lib/middleware"
+	"github.com/axelrhd/hagg-lib/view"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// signedLink generates a signed URL inside the URLSigning middleware.
+func signedLink(t *testing.T, base, p string, ttl time.Duration) string {
+	t.Helper()
+
+	var link string
+	h := middleware.URLSigning(testKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		link = view.SignedURL(r, p, ttl)
+	}))
+
+	var mw func(http.Handler) http.Handler = func(next http.Handler) http.Handler { return next }
+	if base != "" {
+		mw = middleware.BasePath(base)
+	}
+	mw(h).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	return link
+}
+
+// TestSignedURL tests signing and verification end-to-end
+func TestSignedURL(t *testing.T) {
+	valid := signedLink(t, "/app", "/downloads/report.pdf?user=42", time.Hour)
+	if !strings.HasPrefix(valid, "/app/downloads/report.pdf?expires=") || !strings.Contains(valid, "&signature=") {
+		t.Fatalf("unexpected signed URL %q", valid)
+	}
+
+	expired := signedLink(t, "/app", "/downloads/report.pdf", -time.Minute)
+
+	tests := []struct {
+		name         string
+		setup        func(next http.Handler) http.Handler
+		target       string
+		expectedCode int
+	}{
+		{"valid (prefix stripped)", withBase, strings.TrimPrefix(valid, "/app"), http.StatusOK},
+		{"valid (prefix kept)", withBase, valid, http.StatusOK},
+		{"valid (StripBasePath)", middleware.StripBasePath("/app"), valid, http.StatusOK},
+		{"tampered query", withBase, strings.Replace(strings.TrimPrefix(valid, "/app"), "user=42", "user=43", 1), http.StatusForbidden},
+		{"tampered path", withBase, strings.Replace(strings.TrimPrefix(valid, "/app"), "report", "secret", 1), http.StatusForbidden},
+		{"tampered expiry", withBase, strings.Replace(strings.TrimPrefix(valid, "/app"), "expires=", "expires=9", 1), http.StatusForbidden},
+		{"missing signature", withBase, "/downloads/report.pdf?user=42", http.StatusForbidden},
+		{"expired", withBase, strings.TrimPrefix(expired, "/app"), http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			h := tt.setup(middleware.URLSigning(testKey)(middleware.RequireSignedURL(nil)(ok)))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", tt.target, nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+// TestSignedURL_EscapedPath tests that paths with spaces and unicode verify
+// whether they were signed raw or escaped
+func TestSignedURL_EscapedPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"raw space", "/files/a b.pdf", "/app/files/a%20b.pdf?expires="},
+		{"escaped space", "/files/a%20b.pdf", "/app/files/a%20b.pdf?expires="},
+		{"raw unicode", "/files/bücher.pdf", "/app/files/b%C3%BCcher.pdf?expires="},
+		{"escaped slash kept", "/files/a%2Fb", "/app/files/a%2Fb?expires="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := signedLink(t, "/app", tt.path, time.Hour)
+			if !strings.HasPrefix(link, tt.expected) {
+				t.Fatalf("expected prefix %q, got %q", tt.expected, link)
+			}
+
+			ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			h := withBase(middleware.URLSigning(testKey)(middleware.RequireSignedURL(nil)(ok)))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", strings.TrimPrefix(link, "/app"), nil))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+// withBase injects the basePath "/app" (paths may or may not include it).
+func withBase(next http.Handler) http.Handler { return middleware.BasePath("/app")(next) }
+
+// TestSignedURL_NoKey tests the missing key configuration error
+func TestSignedURL_NoKey(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil).WithContext(context.Background())
+
+	if u, err := view.SignedURLErr(req, "/x", time.Hour); !errors.Is(err, view.ErrNoSigningKey) || u != "" {
+		t.Errorf("SignedURLErr: expected ErrNoSigningKey, got %q %v", u, err)
+	}
+
+	withOrigin := req.WithContext(context.WithValue(req.Context(), ctxkeys.Origin, "https://example.com"))
+	if _, err := view.AbsoluteSignedURLErr(withOrigin, "/x", time.Hour); !errors.Is(err, view.ErrNoSigningKey) {
+		t.Errorf("AbsoluteSignedURLErr: expected ErrNoSigningKey, got %v", err)
+	}
+	if _, err := view.AbsoluteSignedURLErr(req, "/x", time.Hour); !errors.Is(err, view.ErrNoOrigin) {
+		t.Errorf("AbsoluteSignedURLErr: expected ErrNoOrigin, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without signing key")
+		}
+	}()
+	view.SignedURL(req, "/x", time.Hour)
+}
@@ -6,6 +6,15 @@
 // # URL Helpers
 //
 //   - URLString: Get basePath-aware URL as string (for hx-*, forms, JS, redirects)
+//   - URL: Builder for URLs with escaped segments, query params and fragments
+//   - Route: URL of a named route (see Registry)
+//   - AbsoluteURL: scheme://host + basePath-aware URL (emails, canonical links)
+//   - SignedURL: URL with HMAC signature and expiry (see middleware.RequireSignedURL)
+//
+// # Navigation Helpers
+//
+//   - IsActive / IsActivePrefix: Compare the current page with a path
+//   - NavLink: Link with aria-current="page" and class "active"
 //
 // # Usage Example
 //
@@ -16,39 +25,94 @@
 //	A(Href(view.URLString(req, "/login")), g.Text("Login"))  // href="/app/login"
 //
 //	// In HTMX attributes
-//	hx.Post(view.URLString(req, "/htmx/save"))  // hx-post="/app/htmx/save"
+//	hx.Post(req, "/htmx/save")  // hx-post="/app/htmx/save" (see hx package)
 //
 //	// In redirects
 //	http.Redirect(w, r, view.URLString(req, "/"), http.StatusSeeOther)
 //
-// # Without BasePath Middleware
+//	// Sort link that keeps the current filters
+//	view.URL(req, "/users").KeepQuery().Set("sort", "name").Del("page").String()
+//	// "/app/users?q=alice&sort=name"
+//
+// # Path Joining
+//
+// Paths are app-relative: "/login" and "login" both yield "/app/login",
+// "" yields the app root "/app". Query and fragment are kept; absolute URLs
+// ("https://...", "//host/...") are returned unchanged.
 //
-// If BasePath middleware is not used, these helpers return URLs unchanged.
+// Without BasePath middleware the base is empty: "/login" stays as it is,
+// "login" becomes "/login" and "" becomes "/".
+//
+// The URL builder differs for an empty path: URL(req, "") refers to the
+// current page, so view.URL(req, "").Set("page", 2) yields "?page=2".
 //
 // # Dependencies
 //
-// Requires: stdlib (net/http), ctxkeys package
+// Requires: stdlib (net/http, net/url, crypto/hmac), gomponents, ctxkeys package
 package view
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/axelrhd/hagg-lib/ctxkeys"
 )
 
+// basePath extracts the basePath from the request context.
+// Returns "" if no (or the root) basePath is set.
+func basePath(req *http.Request) string {
+	bp, _ := req.Context().Value(ctxkeys.BasePath).(string)
+	if bp == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(bp, "/")
+}
+
 // withBasePath extracts basePath from request context and prepends it to the path.
+//
+// Joining rules:
+//   - "/app" + "/login" and "/app/" + "login" both yield "/app/login"
+//   - Relative paths are treated as relative to the base ("login" -> "/app/login")
+//   - Query and fragment of p are kept ("/x?a=1#top" -> "/app/x?a=1#top")
+//   - Absolute URLs ("https://...", "//host/...") are returned unchanged
+//   - Empty, query-only and fragment-only paths refer to the app root
+//     ("" -> "/app", "?page=2" -> "/app?page=2"; "/" without base)
 func withBasePath(req *http.Request, p string) string {
-	raw := req.Context().Value(ctxkeys.BasePath)
-	if raw == nil {
-		return p
+	if p == "" || p[0] == '?' || p[0] == '#' {
+		root := basePath(req)
+		if root == "" {
+			root = "/"
+		}
+		return root + p
 	}
+	return joinBasePath(req, p)
+}
 
-	bp, ok := raw.(string)
-	if !ok || bp == "/" {
+// joinBasePath prepends the basePath to p like withBasePath, but always
+// joins with a slash ("" -> "/app/"). Used for absolute and signed URLs.
+func joinBasePath(req *http.Request, p string) string {
+	if isAbsoluteURL(p) {
 		return p
 	}
 
-	return bp + p
+	// Separate path from query/fragment so only the path is joined
+	rest := ""
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p, rest = p[:i], p[i:]
+	}
+
+	return basePath(req) + "/" + strings.TrimPrefix(p, "/") + rest
+}
+
+// isAbsoluteURL reports whether p has a scheme or is protocol-relative.
+func isAbsoluteURL(p string) bool {
+	if strings.HasPrefix(p, "//") {
+		return true
+	}
+	u, err := url.Parse(p)
+	return err == nil && u.Scheme != ""
 }
 
 // URLString returns a basePath-aware URL as a string.
@@ -58,7 +122,130 @@ func withBasePath(req *http.Request, p string) string {
 // Example:
 //
 //	loginURL := view.URLString(req, "/htmx/login")
-//	hx.Post(loginURL)
+//	h.Form(h.Action(loginURL), h.Method("post"))
 func URLString(req *http.Request, p string) string {
 	return withBasePath(req, p)
 }
+
+// URLBuilder builds basePath-aware URLs with path segments, query
+// parameters and a fragment. Create it with URL.
+type URLBuilder struct {
+	req      *http.Request
+	path     string     // App-relative, already escaped path
+	query    url.Values // Query parameters
+	fragment string     // Unescaped fragment
+}
+
+// URL starts a basePath-aware URL for the app-relative path p.
+//
+// p may contain a query string and fragment. Additional segments are
+// formatted with fmt.Sprint, path-escaped and appended:
+//
+//	view.URL(req, "/users", 42, "edit").String()     // "/app/users/42/edit"
+//	view.URL(req, "/files", "a/b c").String()        // "/app/files/a%2Fb%20c"
+//	view.URL(req, "/search?q=go").Add("tag", "x")    // "/app/search?q=go&tag=x"
+//	view.URL(req, "").KeepQuery().Set("page", 2)     // "?page=2" (current page)
+//
+// p is written by the app, not the client, so it is parsed leniently:
+// malformed query pairs (e.g. "a=%zz") and a malformed fragment escape are
+// dropped; the valid parameters are kept.
+func URL(req *http.Request, p string, segments ...any) *URLBuilder {
+	b := &URLBuilder{req: req, query: url.Values{}}
+
+	if i := strings.IndexByte(p, '#'); i >= 0 {
+		b.fragment, _ = url.PathUnescape(p[i+1:])
+		p = p[:i]
+	}
+	if i := strings.IndexByte(p, '?'); i >= 0 {
+		b.query, _ = url.ParseQuery(p[i+1:])
+		p = p[:i]
+	}
+	b.path = p
+
+	return b.Segment(segments...)
+}
+
+// Segment appends path-escaped segments.
+// Returns self for method chaining.
+func (b *URLBuilder) Segment(segments ...any) *URLBuilder {
+	for _, s := range segments {
+		b.path = strings.TrimSuffix(b.path, "/") + "/" + url.PathEscape(fmt.Sprint(s))
+	}
+	return b
+}
+
+// KeepQuery copies query parameters of the current request. Without keys,
+// all parameters are copied; otherwise only the listed ones. Parameters
+// already set on the builder are replaced.
+// Returns self for method chaining.
+//
+// Example (pagination link that keeps sort and filter):
+//
+//	view.URL(req, "/users").KeepQuery().Set("page", 3)
+func (b *URLBuilder) KeepQuery(keys ...string) *URLBuilder {
+	current := b.req.URL.Query()
+	if len(keys) == 0 {
+		for k := range current {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		if vs, ok := current[k]; ok {
+			b.query[k] = append([]string(nil), vs...)
+		}
+	}
+	return b
+}
+
+// Set replaces all values of a query parameter.
+// Returns self for method chaining.
+func (b *URLBuilder) Set(key string, value any) *URLBuilder {
+	b.query.Set(key, fmt.Sprint(value))
+	return b
+}
+
+// Add appends a value to a query parameter.
+// Returns self for method chaining.
+func (b *URLBuilder) Add(key string, value any) *URLBuilder {
+	b.query.Add(key, fmt.Sprint(value))
+	return b
+}
+
+// Del removes query parameters.
+// Returns self for method chaining.
+func (b *URLBuilder) Del(keys ...string) *URLBuilder {
+	for _, k := range keys {
+		b.query.Del(k)
+	}
+	return b
+}
+
+// Fragment sets the fragment (without "#").
+// Returns self for method chaining.
+func (b *URLBuilder) Fragment(fragment string) *URLBuilder {
+	b.fragment = fragment
+	return b
+}
+
+// String returns the URL. Query parameters are sorted by key.
+// An empty path refers to the current page ("?page=2", "#top", "").
+func (b *URLBuilder) String() string {
+	return b.format(func(req *http.Request, p string) string {
+		if p == "" {
+			return ""
+		}
+		return withBasePath(req, p)
+	})
+}
+
+// format joins the path with join and appends query and fragment.
+func (b *URLBuilder) format(join func(*http.Request, string) string) string {
+	s := join(b.req, b.path)
+	if len(b.query) > 0 {
+		s += "?" + b.query.Encode()
+	}
+	if b.fragment != "" {
+		s += "#" + (&url.URL{Fragment: b.fragment}).EscapedFragment()
+	}
+	return s
+}
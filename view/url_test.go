@@ -0,0 +1,91 @@
+package view
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axelrhd/hagg-lib/ctxkeys"
+)
+
+// newRequest creates a request with an optional basePath in the context.
+func newRequest(target, base string) *http.Request {
+	req := httptest.NewRequest("GET", target, nil)
+	if base != "" {
+		req = req.WithContext(context.WithValue(req.Context(), ctxkeys.BasePath, base))
+	}
+	return req
+}
+
+// TestURLString tests basePath joining
+func TestURLString(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		path     string
+		expected string
+	}{
+		{"no base", "", "/login", "/login"},
+		{"root base", "/", "/login", "/login"},
+		{"simple", "/app", "/login", "/app/login"},
+		{"base with trailing slash", "/app/", "/login", "/app/login"},
+		{"relative path", "/app", "login", "/app/login"},
+		{"relative path without base", "", "login", "/login"},
+		{"root path", "/app", "/", "/app/"},
+		{"trailing slash kept", "/app", "/users/", "/app/users/"},
+		{"query and fragment", "/app", "/search?q=a/b#top", "/app/search?q=a/b#top"},
+		{"absolute URL", "/app", "https://example.com/x", "https://example.com/x"},
+		{"protocol-relative URL", "/app", "//cdn.example.com/x.js", "//cdn.example.com/x.js"},
+		{"empty", "/app", "", "/app"},
+		{"empty without base", "", "", "/"},
+		{"fragment only", "/app", "#top", "/app#top"},
+		{"query only", "/app", "?page=2", "/app?page=2"},
+		{"query only without base", "", "?page=2", "/?page=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := URLString(newRequest("/", tt.base), tt.path)
+			if got != tt.expected {
+				t.Errorf("URLString(%q) with base %q = %q, expected %q", tt.path, tt.base, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestURL tests the URL builder
+func TestURL(t *testing.T) {
+	req := newRequest("/users?q=alice&sort=age&page=3&tag=a&tag=b", "/app/")
+
+	tests := []struct {
+		name     string
+		build    func() *URLBuilder
+		expected string
+	}{
+		{"path only", func() *URLBuilder { return URL(req, "/users") }, "/app/users"},
+		{"escaped segments", func() *URLBuilder { return URL(req, "/files", "a/b c", 42) }, "/app/files/a%2Fb%20c/42"},
+		{"segment method", func() *URLBuilder { return URL(req, "/users/").Segment(7, "edit") }, "/app/users/7/edit"},
+		{"query params", func() *URLBuilder { return URL(req, "/search").Set("q", "a&b").Add("n", 1) }, "/app/search?n=1&q=a%26b"},
+		{"query in path", func() *URLBuilder { return URL(req, "/search?q=go").Add("tag", "x") }, "/app/search?q=go&tag=x"},
+		{"keep all query", func() *URLBuilder {
+			return URL(req, "/users").KeepQuery().Set("sort", "name").Del("page")
+		}, "/app/users?q=alice&sort=name&tag=a&tag=b"},
+		{"keep selected query", func() *URLBuilder {
+			return URL(req, "/users").KeepQuery("q", "missing").Set("page", 4)
+		}, "/app/users?page=4&q=alice"},
+		{"fragment", func() *URLBuilder { return URL(req, "/docs").Fragment("section 2") }, "/app/docs#section%202"},
+		{"fragment in path", func() *URLBuilder { return URL(req, "/docs#intro").Set("v", 2) }, "/app/docs?v=2#intro"},
+		{"current page", func() *URLBuilder { return URL(req, "").KeepQuery("q").Set("page", 2) }, "?page=2&q=alice"},
+		{"current page fragment", func() *URLBuilder { return URL(req, "#top") }, "#top"},
+		{"malformed query dropped", func() *URLBuilder { return URL(req, "/search?a=%zz&b=1") }, "/app/search?b=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.build().String(); got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}